@@ -0,0 +1,536 @@
+/*
+Projeto: GoSketch - Animações
+Descrição: Carregamento e reprodução de GIFs animados e APNGs como sequências de quadros,
+com atrasos por quadro e tratamento de descarte (disposal) e mistura (blend)
+*/
+
+package gosketch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+)
+
+// Animation representa uma imagem animada decodificada em quadros
+// Pode ser desenhada diretamente com Image, que usa o quadro correspondente
+// ao tempo de reprodução atual.
+type Animation struct {
+	frames    []*SketchImage
+	delays    []int // Duração de cada quadro em milissegundos
+	duration  int   // Duração total de uma repetição em milissegundos
+	loops     int   // Número de repetições (0 = infinito)
+	playing   bool
+	startedAt int // Millis() quando a reprodução foi iniciada ou retomada
+	position  int // Posição de reprodução acumulada até a última pausa
+	width     int
+	height    int
+}
+
+// Atraso mínimo de quadro usado pelos navegadores para GIFs com atraso muito pequeno
+const (
+	gifMinDelay     = 20  // Em milissegundos; atrasos menores são tratados como gifDefaultDelay
+	gifDefaultDelay = 100 // Em milissegundos
+)
+
+// pngSignature são os 8 bytes iniciais de qualquer arquivo PNG
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// LoadAnimation carrega um GIF animado ou APNG do sistema de arquivos
+// Imagens estáticas são carregadas como uma animação de um único quadro.
+// A animação começa tocando, repetindo conforme definido no arquivo.
+func LoadAnimation(path string) *Animation {
+	defer trackAsset(path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao abrir animação '%s': %w", path, err))
+		return nil
+	}
+	defer file.Close()
+
+	anim, err := decodeAnimation(file)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar animação '%s': %w", path, err))
+		return nil
+	}
+	return anim
+}
+
+// LoadAnimationReader carrega um GIF animado ou APNG a partir de um io.Reader
+func LoadAnimationReader(r io.Reader) *Animation {
+	if r == nil {
+		reportError(newError(ErrNilArgument, "tentativa de carregar animação de um reader nulo"))
+		return nil
+	}
+
+	anim, err := decodeAnimation(r)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar animação: %w", err))
+		return nil
+	}
+	return anim
+}
+
+// decodeAnimation detecta o formato pelo conteúdo e decodifica os quadros
+func decodeAnimation(r io.Reader) (*Animation, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(len(pngSignature))
+
+	switch {
+	case bytes.HasPrefix(header, []byte("GIF8")):
+		return decodeGIFAnimation(br)
+	case bytes.Equal(header, []byte(pngSignature)):
+		return decodeAPNGAnimation(br)
+	default:
+		// Outros formatos são tratados como imagens estáticas
+		img, err := decodeImage(br)
+		if err != nil {
+			return nil, err
+		}
+		return newAnimation([]image.Image{img}, []int{gifDefaultDelay}, 0), nil
+	}
+}
+
+// decodeGIFAnimation compõe os quadros de um GIF aplicando os métodos de descarte
+func decodeGIFAnimation(r io.Reader) (*Animation, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Image) == 0 {
+		return nil, errors.New(tr("GIF sem quadros"))
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		for _, frame := range g.Image {
+			bounds = bounds.Union(frame.Bounds())
+		}
+	}
+
+	composed := image.NewRGBA(bounds)
+	frames := make([]image.Image, len(g.Image))
+	delays := make([]int, len(g.Image))
+
+	for i, frame := range g.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(composed)
+		}
+
+		draw.Draw(composed, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		frames[i] = cloneRGBA(composed)
+
+		delays[i] = gifDefaultDelay
+		if i < len(g.Delay) && g.Delay[i]*10 >= gifMinDelay {
+			delays[i] = g.Delay[i] * 10 // O GIF usa centésimos de segundo
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(composed, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			composed = previous
+		}
+	}
+
+	// LoopCount: 0 = infinito, -1 = toca uma vez, n = repete n vezes além da primeira
+	loops := 0
+	if g.LoopCount < 0 {
+		loops = 1
+	} else if g.LoopCount > 0 {
+		loops = g.LoopCount + 1
+	}
+
+	return newAnimation(frames, delays, loops), nil
+}
+
+// pngChunk representa um chunk bruto de um arquivo PNG
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// apngFrame guarda o controle (fcTL) e os dados comprimidos de um quadro APNG
+type apngFrame struct {
+	width, height int
+	x, y          int
+	delay         int
+	disposeOp     byte
+	blendOp       byte
+	data          []byte
+}
+
+// Operações de descarte e mistura da especificação APNG
+const (
+	apngDisposeNone       = 0
+	apngDisposeBackground = 1
+	apngDisposePrevious   = 2
+	apngBlendSource       = 0
+)
+
+// decodeAPNGAnimation lê os chunks acTL/fcTL/fdAT de um APNG e compõe os quadros
+// Cada quadro é remontado como um PNG independente e decodificado com image/png.
+// Arquivos PNG sem acTL são carregados como um único quadro.
+func decodeAPNGAnimation(r io.Reader) (*Animation, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, err := readPNGChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ihdr     []byte
+		headers  []pngChunk // Chunks compartilhados por todos os quadros (PLTE, tRNS, ...)
+		animated bool
+		plays    int
+		frames   []*apngFrame
+		current  *apngFrame
+		seenIDAT bool
+	)
+
+	for _, c := range chunks {
+		switch c.typ {
+		case "IHDR":
+			ihdr = c.data
+		case "acTL":
+			if len(c.data) < 8 {
+				return nil, errors.New(tr("chunk acTL inválido"))
+			}
+			animated = true
+			plays = int(binary.BigEndian.Uint32(c.data[4:8]))
+		case "fcTL":
+			frame, err := parseFCTL(c.data)
+			if err != nil {
+				return nil, err
+			}
+			current = frame
+			frames = append(frames, frame)
+		case "IDAT":
+			seenIDAT = true
+			// A imagem padrão só faz parte da animação se for precedida por um fcTL
+			if current != nil {
+				current.data = append(current.data, c.data...)
+			}
+		case "fdAT":
+			if current == nil || len(c.data) < 4 {
+				return nil, errors.New(tr("chunk fdAT inesperado"))
+			}
+			current.data = append(current.data, c.data[4:]...) // Ignora o número de sequência
+		case "IEND":
+		default:
+			if !seenIDAT {
+				headers = append(headers, c)
+			}
+		}
+	}
+
+	if !animated || len(frames) == 0 {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return newAnimation([]image.Image{img}, []int{gifDefaultDelay}, 0), nil
+	}
+
+	if len(ihdr) < 13 {
+		return nil, errors.New(tr("chunk IHDR inválido"))
+	}
+	width := int(binary.BigEndian.Uint32(ihdr[0:4]))
+	height := int(binary.BigEndian.Uint32(ihdr[4:8]))
+
+	composed := image.NewRGBA(image.Rect(0, 0, width, height))
+	images := make([]image.Image, len(frames))
+	delays := make([]int, len(frames))
+
+	for i, frame := range frames {
+		img, err := decodeAPNGFrame(ihdr, headers, frame)
+		if err != nil {
+			return nil, fmt.Errorf(tr("erro no quadro %d: %w"), i, err)
+		}
+
+		rect := image.Rect(frame.x, frame.y, frame.x+frame.width, frame.y+frame.height)
+
+		disposeOp := frame.disposeOp
+		if i == 0 && disposeOp == apngDisposePrevious {
+			disposeOp = apngDisposeBackground
+		}
+
+		var previous *image.RGBA
+		if disposeOp == apngDisposePrevious {
+			previous = cloneRGBA(composed)
+		}
+
+		op := draw.Over
+		if frame.blendOp == apngBlendSource {
+			op = draw.Src
+		}
+		draw.Draw(composed, rect, img, img.Bounds().Min, op)
+		images[i] = cloneRGBA(composed)
+		delays[i] = frame.delay
+
+		switch disposeOp {
+		case apngDisposeBackground:
+			draw.Draw(composed, rect, image.Transparent, image.Point{}, draw.Src)
+		case apngDisposePrevious:
+			composed = previous
+		}
+	}
+
+	return newAnimation(images, delays, plays), nil
+}
+
+// readPNGChunks separa o conteúdo de um arquivo PNG em chunks
+func readPNGChunks(data []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, errors.New(tr("assinatura PNG inválida"))
+	}
+
+	var chunks []pngChunk
+	pos := len(pngSignature)
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		typ := string(data[pos+4 : pos+8])
+		end := pos + 8 + length + 4 // Dados + CRC
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf(tr("chunk '%s' truncado"), typ)
+		}
+		chunks = append(chunks, pngChunk{typ: typ, data: data[pos+8 : pos+8+length]})
+		pos = end
+		if typ == "IEND" {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+// parseFCTL lê um chunk de controle de quadro APNG
+func parseFCTL(data []byte) (*apngFrame, error) {
+	if len(data) < 26 {
+		return nil, errors.New(tr("chunk fcTL inválido"))
+	}
+
+	delayNum := int(binary.BigEndian.Uint16(data[20:22]))
+	delayDen := int(binary.BigEndian.Uint16(data[22:24]))
+	if delayDen == 0 {
+		delayDen = 100 // Pela especificação, denominador 0 significa centésimos de segundo
+	}
+	delay := delayNum * 1000 / delayDen
+	if delay < 10 {
+		delay = 10
+	}
+
+	return &apngFrame{
+		width:     int(binary.BigEndian.Uint32(data[4:8])),
+		height:    int(binary.BigEndian.Uint32(data[8:12])),
+		x:         int(binary.BigEndian.Uint32(data[12:16])),
+		y:         int(binary.BigEndian.Uint32(data[16:20])),
+		delay:     delay,
+		disposeOp: data[24],
+		blendOp:   data[25],
+	}, nil
+}
+
+// decodeAPNGFrame monta um PNG independente com os dados de um quadro e o decodifica
+func decodeAPNGFrame(ihdr []byte, headers []pngChunk, frame *apngFrame) (image.Image, error) {
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+
+	header := append([]byte(nil), ihdr...)
+	binary.BigEndian.PutUint32(header[0:4], uint32(frame.width))
+	binary.BigEndian.PutUint32(header[4:8], uint32(frame.height))
+	writePNGChunk(&buf, "IHDR", header)
+
+	for _, c := range headers {
+		writePNGChunk(&buf, c.typ, c.data)
+	}
+	writePNGChunk(&buf, "IDAT", frame.data)
+	writePNGChunk(&buf, "IEND", nil)
+
+	return png.Decode(&buf)
+}
+
+// writePNGChunk escreve um chunk PNG com tamanho, tipo, dados e CRC
+func writePNGChunk(w io.Writer, typ string, data []byte) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	w.Write(length[:])
+
+	crc := crc32.NewIEEE()
+	io.WriteString(crc, typ)
+	crc.Write(data)
+
+	io.WriteString(w, typ)
+	w.Write(data)
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	w.Write(sum[:])
+}
+
+// cloneRGBA cria uma cópia independente de uma imagem RGBA
+func cloneRGBA(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	copy(dst.Pix, src.Pix)
+	return dst
+}
+
+// newAnimation converte os quadros compostos em SketchImages e inicia a reprodução
+func newAnimation(images []image.Image, delays []int, loops int) *Animation {
+	anim := &Animation{
+		frames:    make([]*SketchImage, len(images)),
+		delays:    delays,
+		loops:     loops,
+		playing:   true,
+		startedAt: Millis(),
+	}
+
+	for i, img := range images {
+		anim.frames[i] = newSketchImage(img)
+		anim.duration += delays[i]
+	}
+	anim.width = anim.frames[0].width
+	anim.height = anim.frames[0].height
+
+	return anim
+}
+
+// sketchImage retorna o quadro atual para que a animação possa ser desenhada por Image
+func (a *Animation) sketchImage() *SketchImage {
+	if a == nil {
+		return nil
+	}
+	return a.FrameAt(a.Position())
+}
+
+// Width retorna a largura da animação
+func (a *Animation) Width() int {
+	return a.width
+}
+
+// Height retorna a altura da animação
+func (a *Animation) Height() int {
+	return a.height
+}
+
+// NumFrames retorna o número de quadros da animação
+func (a *Animation) NumFrames() int {
+	return len(a.frames)
+}
+
+// Duration retorna a duração de uma repetição da animação em milissegundos
+func (a *Animation) Duration() int {
+	return a.duration
+}
+
+// Delay retorna a duração do quadro i em milissegundos
+func (a *Animation) Delay(i int) int {
+	if i < 0 || i >= len(a.delays) {
+		reportError(newError(ErrOutOfBounds, "índice de quadro fora dos limites: %d (a animação tem %d quadros)", i, len(a.delays)))
+		return 0
+	}
+	return a.delays[i]
+}
+
+// Frame retorna o quadro i da animação
+func (a *Animation) Frame(i int) *SketchImage {
+	if i < 0 || i >= len(a.frames) {
+		reportError(newError(ErrOutOfBounds, "índice de quadro fora dos limites: %d (a animação tem %d quadros)", i, len(a.frames)))
+		return nil
+	}
+	return a.frames[i]
+}
+
+// FrameAt retorna o quadro exibido após millis milissegundos de reprodução
+// Respeita o número de repetições: ao terminar, permanece no último quadro.
+func (a *Animation) FrameAt(millis int) *SketchImage {
+	return a.frames[a.frameIndexAt(millis)]
+}
+
+// frameIndexAt calcula o índice do quadro para uma posição de reprodução
+func (a *Animation) frameIndexAt(millis int) int {
+	if a.duration <= 0 || millis < 0 {
+		return 0
+	}
+	if a.loops > 0 && millis >= a.duration*a.loops {
+		return len(a.frames) - 1
+	}
+
+	t := millis % a.duration
+	for i, delay := range a.delays {
+		if t < delay {
+			return i
+		}
+		t -= delay
+	}
+	return len(a.frames) - 1
+}
+
+// Position retorna a posição de reprodução atual em milissegundos
+func (a *Animation) Position() int {
+	if a.playing {
+		return a.position + Millis() - a.startedAt
+	}
+	return a.position
+}
+
+// CurrentFrame retorna o índice do quadro exibido na posição de reprodução atual
+func (a *Animation) CurrentFrame() int {
+	return a.frameIndexAt(a.Position())
+}
+
+// Play retoma a reprodução a partir da posição atual
+func (a *Animation) Play() {
+	if !a.playing {
+		a.startedAt = Millis()
+		a.playing = true
+	}
+}
+
+// Pause congela a animação no quadro atual
+func (a *Animation) Pause() {
+	if a.playing {
+		a.position = a.Position()
+		a.playing = false
+	}
+}
+
+// IsPlaying retorna se a animação está sendo reproduzida
+func (a *Animation) IsPlaying() bool {
+	return a.playing
+}
+
+// Rewind volta a reprodução para o primeiro quadro
+func (a *Animation) Rewind() {
+	a.position = 0
+	a.startedAt = Millis()
+}
+
+// Loop faz a animação repetir indefinidamente
+func (a *Animation) Loop() {
+	a.loops = 0
+}
+
+// NoLoop faz a animação tocar uma única vez e parar no último quadro
+func (a *Animation) NoLoop() {
+	a.loops = 1
+}
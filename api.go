@@ -13,7 +13,8 @@ Dependência mínima: Ebiten para janela e desenho 2D.
 package gosketch
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"image/color"
 	"log"
 	"runtime/debug"
@@ -24,27 +25,29 @@ import (
 )
 
 // Canvas interno: contexto de desenho
+// Width e Height são lógicos; img tem Width*density x Height*density pixels físicos.
 type Canvas struct {
 	Width, Height int
 	img           *ebiten.Image
+	density       float64
 }
 
-// Set implements the shapes.Canvas interface
+// Set implements the shapes.Canvas interface (physical pixels)
 func (c *Canvas) Set(x, y int, clr color.Color) {
 	// Verifica se está dentro dos limites do canvas
-	if x >= 0 && x < c.Width && y >= 0 && y < c.Height {
+	if x >= 0 && x < c.GetWidth() && y >= 0 && y < c.GetHeight() {
 		c.img.Set(x, y, clr)
 	}
 }
 
-// GetWidth returns the canvas width - implements shapes.Canvas interface
+// GetWidth returns the canvas width in physical pixels - implements shapes.Canvas interface
 func (c *Canvas) GetWidth() int {
-	return c.Width
+	return c.img.Bounds().Dx()
 }
 
-// GetHeight returns the canvas height - implements shapes.Canvas interface
+// GetHeight returns the canvas height in physical pixels - implements shapes.Canvas interface
 func (c *Canvas) GetHeight() int {
-	return c.Height
+	return c.img.Bounds().Dy()
 }
 
 // Estado global da API
@@ -57,41 +60,56 @@ var (
 	strokeWeight  float64     = 1
 	fillEnabled   bool        = true
 	strokeEnabled bool        = true
-	lastFrameTime time.Time   = time.Now()
-	frameRate     float64     = 0
+	frameRate     float64     = 0 // Média móvel da taxa de quadros desenhados
 	errorHandler  func(error) = defaultErrorHandler
 	isLooping     bool        = true  // Controla se o draw loop está ativo ou pausado
 	redrawCount   int         = 0     // Contador para múltiplas execuções do draw quando solicitado
     targetFPS    int         = 60
-    maxFPS       float64     = 240.0
-    lastDrawTime time.Time   = time.Now()
-    minFrameTime float64     = 0.0001
     sketchStartTime time.Time = time.Now() // Armazena o momento de início do sketch
 
 )
 
 // defaultErrorHandler é o tratador de erros padrão que registra o erro e o stack trace
 func defaultErrorHandler(err error) {
-	log.Printf("ERRO NA GOSKETCH: %v\n", err)
-	log.Printf("Stack trace:\n%s\n", debug.Stack())
+	log.Printf(tr("ERRO NA GOSKETCH: %v\n"), err)
+	log.Printf(tr("Stack trace:\n%s\n"), debug.Stack())
 }
 
 // SetErrorHandler permite definir um tratador de erros personalizado
+// Se erros forem reportados por outras goroutines (carregamento de assets, por exemplo),
+// o tratador deve ser seguro para chamadas concorrentes.
 func SetErrorHandler(handler func(error)) {
+	reportMu.Lock()
+	defer reportMu.Unlock()
+
 	if handler != nil {
 		errorHandler = handler
 	} else {
 		errorHandler = defaultErrorHandler
 	}
-	
-	// Também configura o error handler para cores
-	SetColorErrorReporter(errorHandler)
 }
 
 // reportError reporta um erro usando o errorHandler atual
+// Mensagens repetidas são agrupadas (veja SetErrorRateLimit) e, no modo estrito,
+// o erro encerra o sketch.
+// Pode ser chamada de qualquer goroutine. O tratador é chamado fora do lock, na
+// goroutine que reportou o erro, para que possa usar a API (e reportar novos erros)
+// sem travar; erros de goroutines diferentes podem chegar a ele ao mesmo tempo.
 func reportError(err error) {
-	if errorHandler != nil {
-		errorHandler(err)
+	recordPreloadError(err)
+	failStrict(err)
+
+	err, ok := throttleError(err)
+	if !ok {
+		return
+	}
+
+	reportMu.Lock()
+	handler := errorHandler
+	reportMu.Unlock()
+
+	if handler != nil {
+		handler(err)
 	}
 }
 
@@ -112,22 +130,30 @@ func Draw(f func()) {
 }
 
 // CreateCanvas define largura e altura do canvas
+// A resolução volta ao padrão de GetDPI; use DPI depois para definir outra.
 func CreateCanvas(w, h int) {
 	if w <= 0 || h <= 0 {
-		reportError(fmt.Errorf("dimensões de canvas inválidas: %dx%d - as dimensões devem ser positivas", w, h))
+		reportError(newError(ErrInvalidDimensions, "dimensões de canvas inválidas: %dx%d - as dimensões devem ser positivas", w, h))
 		w = 100
 		h = 100
 	}
-	canvas = &Canvas{Width: w, Height: h, img: ebiten.NewImage(w, h)}
+	img := ebiten.NewImage(physicalSize(w, pixelDensity), physicalSize(h, pixelDensity))
+	canvas = &Canvas{Width: w, Height: h, img: img, density: pixelDensity}
+	// A resolução de um canvas anterior (CreateCanvasPaper ou DPI) não vale para este
+	canvasDPI = 0
 }
 
 // Background preenche todo o canvas com a cor especificada
 func Background(c ColorValue) {
+	if displayList != nil {
+		recordBackground(ParseColorValue(c))
+		return
+	}
 	if canvas != nil {
 		color := ParseColorValue(c)
 		canvas.img.Fill(color)
 	} else {
-		reportError(fmt.Errorf("tentativa de definir background sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de definir background sem canvas inicializado"))
 	}
 }
 
@@ -180,15 +206,17 @@ func StrokeColor(c color.Color) {
 func NoStroke() { strokeEnabled = false }
 
 // StrokeWeight define a espessura do contorno para formas subsequentes
+// A espessura está na unidade atual (veja Units).
 func StrokeWeight(w float64) {
 	if w < 0 {
-		reportError(fmt.Errorf("espessura de contorno inválida: %.2f - deve ser não-negativa", w))
+		reportError(newError(ErrInvalidArgument, "espessura de contorno inválida: %.2f - deve ser não-negativa", w))
 		w = 1
 	}
 	strokeWeight = w
 }
 
 // GetFrameRate retorna o frame rate atual (frames por segundo)
+// O valor é uma média móvel dos intervalos entre quadros, sem o ruído de um único intervalo.
 func GetFrameRate() float64 {
 	return frameRate
 }
@@ -196,112 +224,202 @@ func GetFrameRate() float64 {
 // RenderShape executa o método Draw de qualquer Shape da nova API
 func RenderShape(s shapes.Shape) {
 	if s == nil {
-		reportError(fmt.Errorf("tentativa de renderizar uma shape nula"))
+		reportError(newError(ErrNilArgument, "tentativa de renderizar uma shape nula"))
+		return
+	}
+	// Coordenadas na unidade atual (veja Units) são convertidas para pixels
+	s = unitShape(s)
+	if displayList != nil {
+		recordShape(s)
 		return
 	}
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de renderizar sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de renderizar sem canvas inicializado"))
 		return
 	}
 	
 	// Captura e reporta possíveis pânicos durante o desenho
 	defer func() {
 		if r := recover(); r != nil {
-			reportError(fmt.Errorf("pânico durante renderização: %v", r))
+			reportError(newError(ErrPanic, "pânico durante renderização: %v", r))
 		}
 	}()
 	
-	s.Draw(canvas, fillColor, strokeColor, fillEnabled, strokeEnabled, strokeWeight)
+	// Coordenadas lógicas são convertidas para os pixels físicos do canvas
+	s, target := physicalShape(s)
+	s.Draw(target, fillColor, strokeColor, fillEnabled, strokeEnabled, toPixels(strokeWeight)*canvas.density)
 }
 
 // Run inicia o loop principal da janela Ebiten
+// Retorna nil quando o sketch termina normalmente (Exit, fechamento da janela ou SIGINT)
+// e o erro de ExitWithError ou de preload/setup caso contrário.
+// O Ebiten não permite iniciar o loop duas vezes: depois que o sketch rodou, novas
+// chamadas a Run, RunContext ou RunFrames retornam um erro ErrInvalidState.
 func Run() error {
-	// Executa o setup antes de iniciar o loop
-	if setupFn != nil {
-		defer func() {
-			if r := recover(); r != nil {
-				err := fmt.Errorf("pânico durante setup: %v", r)
-				reportError(err)
-				return
-			}
-		}()
-		
-		setupFn()
-		setupFn = nil
+	return RunContext(context.Background())
+}
+
+// RunContext executa o sketch como Run, mas termina quando ctx é cancelado,
+// retornando um erro que satisfaz errors.Is(err, ctx.Err())
+// Útil para embutir sketches em programas maiores.
+func RunContext(ctx context.Context) error {
+	if ctx == nil {
+		return newError(ErrNilArgument, "contexto nulo passado para RunContext")
+	}
+	return runSketch(ctx, 0)
+}
+
+// RunFrames executa o sketch até que n quadros tenham sido desenhados e retorna
+// Também retorna se NoLoop parar o desenho antes de n quadros, já que nenhum
+// outro quadro seria desenhado.
+// Como Run, só pode ser executada uma vez por processo. Um serviço que gera várias
+// imagens deve rodar um processo por execução ou, sem janela, usar RecordDisplayList
+// e Rasterize.
+func RunFrames(n int) error {
+	if n <= 0 {
+		return newError(ErrInvalidArgument, "número de quadros inválido para RunFrames: %d - deve ser positivo", n)
+	}
+	return runSketch(context.Background(), n)
+}
+
+// runSketch executa preload, setup e o loop do Ebiten, chamando OnExit ao final
+// Se frames > 0, o sketch termina após desenhar esse número de quadros.
+func runSketch(ctx context.Context, frames int) error {
+	if loopRan {
+		return newError(ErrInvalidState, "o sketch já foi executado - Run, RunContext e RunFrames só podem ser chamadas uma vez por processo")
+	}
+	resetRunState()
+	runCtx = ctx
+
+	// O limite é relativo a frameCount, que os testes podem ter avançado antes de Run
+	frameLimit = 0
+	if frames > 0 {
+		frameLimit = frameCount + frames
+	}
+
+	// OnExit é chamado em qualquer encerramento, inclusive por falhas no preload/setup
+	defer runExitHooks()
+
+	// Executa o preload e o setup antes de iniciar o loop
+	if err := runPreload(); err != nil {
+		return err
+	}
+	if err := runSetup(); err != nil {
+		return err
+	}
+
+	if exitPending() {
+		return exitError()
 	}
 	
 	if canvas == nil {
-		err := fmt.Errorf("canvas não criado. Use CreateCanvas no setup")
+		err := newError(ErrNoCanvas, "canvas não criado. Use CreateCanvas no setup")
 		reportError(err)
 		return err
 	}
+
+	watchInterrupt()
+	defer stopWatchingInterrupt()
 	
 	ebiten.SetWindowSize(canvas.Width, canvas.Height)
-	ebiten.SetWindowTitle("Arte Generativa (Go + p5.js API)")
-	return ebiten.RunGame(&internalGame{})
+	ebiten.SetWindowTitle(windowTitle)
+	// Falhas antes deste ponto permitem uma nova tentativa; o loop não
+	loopRan = true
+	err := ebiten.RunGame(&internalGame{})
+	if errors.Is(err, ebiten.Termination) {
+		return nil
+	}
+	return err
+}
+
+// runSetup executa a função de setup, convertendo pânicos em erro
+func runSetup() (err error) {
+	if setupFn == nil {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = newError(ErrPanic, "pânico durante setup: %v", r)
+			reportError(err)
+		}
+	}()
+
+	setupFn()
+	setupFn = nil
+	return nil
 }
 
 // internalGame implementa ebiten.Game chamando drawFn e exibindo o canvas
 type internalGame struct{}
 
+// Update roda em passo fixo (UpdateRate vezes por segundo), separado da renderização
 func (g *internalGame) Update() error {
-	now := time.Now()
-	elapsed := now.Sub(lastFrameTime).Seconds()
-	lastFrameTime = now
-
-	// Protege contra valores irreais
-	if elapsed < minFrameTime {
-		elapsed = minFrameTime
+	// Exit, ExitWithError ou SIGINT encerram o loop
+	if exitPending() {
+		if err := exitError(); err != nil {
+			return err
+		}
+		return ebiten.Termination
 	}
 
-	frameRate = 1.0 / elapsed
+	// Atualiza o estado de entrada e dispara os callbacks de eventos
+	processInput()
+	dispatchWindowResized()
 
-	// Protege contra estouros absurdos
-	if frameRate > maxFPS {
-		frameRate = maxFPS
-	}
+	callHandler("update", updateFn)
 
 	return nil
 }
 
 func (g *internalGame) Draw(screen *ebiten.Image) {
-	// Executa o draw se o loop estiver ativo ou se há contagem de redraws
-	if drawFn != nil && (isLooping || redrawCount > 0) {
+	// Executa o draw se o loop estiver ativo ou se há contagem de redraws,
+	// e se o próximo quadro já é devido segundo FrameRate
+	now := time.Now()
+	if drawFn != nil && (isLooping || redrawCount > 0) && !frameLimitReached() && frameDue(now) {
+		beginFrame(now)
+
 		// Captura e reporta possíveis pânicos durante o desenho
 		defer func() {
 			if r := recover(); r != nil {
-				reportError(fmt.Errorf("pânico durante draw: %v", r))
+				reportError(newError(ErrPanic, "pânico durante draw: %v", r))
 			}
 		}()
 		
 		// Quando isLooping é falso mas temos redrawCount positivo,
 		// executamos a função draw uma vez por frame e decrementamos o contador
 		drawFn()
+
+		// Executa os comandos de desenho enviados por outras goroutines
+		runDrawQueue()
+
+		// Captura o quadro desenhado se houver uma gravação em andamento
+		captureRecordingFrame()
 		
 		// Reseta redrawCount após uso
 		if redrawCount > 0 {
 			redrawCount--
 		}
+	} else if !isLooping {
+		// Com o loop parado, os comandos enfileirados ainda aparecem no canvas
+		runDrawQueue()
 	}
 	
 	if canvas != nil {
-		op := &ebiten.DrawImageOptions{}
-		screen.DrawImage(canvas.img, op)
+		drawCanvasToScreen(screen)
 	}
-    delta := time.Since(lastDrawTime)
-    wait := time.Second/time.Duration(targetFPS) - delta
-    if wait > 0 {
-        time.Sleep(wait)
-    }
-    lastDrawTime = time.Now()
 }
 
 func (g *internalGame) Layout(outsideWidth, outsideHeight int) (int, int) {
     if canvas == nil {
-        reportError(fmt.Errorf("canvas não inicializado ao definir layout"))
+        reportError(newError(ErrNoCanvas, "canvas não inicializado ao definir layout"))
 		return 300, 300 // valor padrão em caso de erro
 	}
-	return canvas.Width, canvas.Height
+
+	// A tela tem o tamanho da janela em pixels do dispositivo; o canvas é escalado
+	// nela conforme WindowScaling
+	updateWindowLayout(outsideWidth, outsideHeight)
+	return int(float64(outsideWidth) * screenDeviceScale), int(float64(outsideHeight) * screenDeviceScale)
 }
 
 // NoLoop para a execução contínua da função draw
@@ -313,6 +431,10 @@ func NoLoop() {
 // Loop reinicia a execução contínua da função draw após uma chamada a NoLoop()
 // Similar à função loop() do p5.js/Processing
 func Loop() {
+    if !isLooping {
+        // O tempo parado não conta como quadros perdidos
+        resetFramePacing()
+    }
     isLooping = true
 }
 
@@ -334,6 +456,7 @@ func Redraw(n ...int) {
             count = n[0]
         }
         redrawCount = count
+        resetFramePacing()
     }
 }
 
@@ -345,17 +468,19 @@ func RedrawOnce() {
     Redraw(1)
 }
 
-// FrameRate define o número de quadros por segundo desejado.
-// Se fps <= 0, libera para rodar o mais rápido possível.
+// vsyncMaxFPS é a maior taxa em que FrameRate mantém a sincronia vertical
+// O Ebiten não informa a taxa de atualização do monitor; 60 Hz é o mínimo comum.
+const vsyncMaxFPS = 60
+
+// FrameRate define o número de quadros desenhados por segundo desejado.
+// O ritmo de draw é independente de Update, que roda na taxa de UpdateRate.
+// Até 60 fps a sincronia vertical fica ativa; acima disso ela é desativada para que
+// a taxa pedida não seja limitada pela atualização do monitor.
+// Se fps <= 0, libera para desenhar o mais rápido possível, sem sincronia vertical.
 func FrameRate(fps int) {
     targetFPS = fps
-    if fps > 0 {
-        ebiten.SetVsyncEnabled(false)
-        ebiten.SetTPS(fps)
-    } else {
-        ebiten.SetVsyncEnabled(true)
-        ebiten.SetTPS(ebiten.SyncWithFPS)
-    }
+    resetFramePacing()
+    ebiten.SetVsyncEnabled(fps > 0 && fps <= vsyncMaxFPS)
 }
 
 // Millis retorna o número de milissegundos desde que o sketch começou a ser executado
@@ -369,7 +494,7 @@ func Millis() int {
 // Ellipse cria e renderiza uma elipse em um único passo
 func Ellipse(x, y, rx, ry float64) {
     if rx <= 0 || ry <= 0 {
-		reportError(fmt.Errorf("raio inválido para elipse: rx=%.2f, ry=%.2f - os raios devem ser positivos", rx, ry))
+		reportError(newError(ErrInvalidDimensions, "raio inválido para elipse: rx=%.2f, ry=%.2f - os raios devem ser positivos", rx, ry))
 		return
 	}
 	shape := shapes.CreateEllipse(x, y, rx, ry)
@@ -379,7 +504,7 @@ func Ellipse(x, y, rx, ry float64) {
 // Circle cria e renderiza um círculo em um único passo (caso especial de Ellipse)
 func Circle(x, y, radius float64) {
 	if radius <= 0 {
-		reportError(fmt.Errorf("raio inválido para círculo: %.2f - o raio deve ser positivo", radius))
+		reportError(newError(ErrInvalidDimensions, "raio inválido para círculo: %.2f - o raio deve ser positivo", radius))
 		return
 	}
 	shape := shapes.CreateCircle(x, y, radius)
@@ -389,7 +514,7 @@ func Circle(x, y, radius float64) {
 // Rectangle cria e renderiza um retângulo em um único passo
 func Rectangle(x, y, w, h float64) {
 	if w <= 0 || h <= 0 {
-		reportError(fmt.Errorf("dimensões inválidas para retângulo: w=%.2f, h=%.2f - as dimensões devem ser positivas", w, h))
+		reportError(newError(ErrInvalidDimensions, "dimensões inválidas para retângulo: w=%.2f, h=%.2f - as dimensões devem ser positivas", w, h))
 		return
 	}
 	shape := shapes.CreateRectangle(x, y, w, h)
@@ -399,7 +524,7 @@ func Rectangle(x, y, w, h float64) {
 // Square cria e renderiza um quadrado em um único passo
 func Square(x, y, size float64) {
 	if size <= 0 {
-		reportError(fmt.Errorf("tamanho inválido para quadrado: %.2f - o tamanho deve ser positivo", size))
+		reportError(newError(ErrInvalidDimensions, "tamanho inválido para quadrado: %.2f - o tamanho deve ser positivo", size))
 		return
 	}
 	shape := shapes.CreateSquare(x, y, size)
@@ -427,7 +552,7 @@ func Triangle(x1, y1, x2, y2, x3, y3 float64) {
 // GetWidth retorna a largura do canvas atual
 func GetWidth() int {
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de obter largura sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de obter largura sem canvas inicializado"))
 		return 0
 	}
 	return canvas.Width
@@ -436,7 +561,7 @@ func GetWidth() int {
 // GetHeight retorna a altura do canvas atual
 func GetHeight() int {
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de obter altura sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de obter altura sem canvas inicializado"))
 		return 0
 	}
 	return canvas.Height
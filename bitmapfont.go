@@ -0,0 +1,428 @@
+/*
+Projeto: GoSketch - Fontes Bitmap
+Descrição: Carregamento de fontes bitmap no formato AngelCode BMFont (.fnt em texto ou XML,
+com páginas PNG) e de fontes em grade fixa (sprite sheets), renderizadas por Text com
+escala inteira e tingidas por TextColor
+*/
+
+package gosketch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// bitmapGlyph descreve a posição de um caractere em uma página da fonte bitmap
+type bitmapGlyph struct {
+	x, y, width, height int
+	xoffset, yoffset    int // Deslocamento a partir da posição da caneta e do topo da linha
+	xadvance            int
+	page                int
+}
+
+// bitmapFont guarda os dados de uma fonte bitmap
+type bitmapFont struct {
+	size       int // Tamanho nominal em pixels; escalas inteiras são múltiplos dele
+	lineHeight int
+	base       int // Distância do topo da linha até a baseline
+	pages      []*image.Alpha
+	glyphs     map[rune]bitmapGlyph
+	kernings   map[[2]rune]int
+}
+
+// bmfontAttr extrai os pares chave=valor de uma linha do formato texto do BMFont
+var bmfontAttr = regexp.MustCompile(`(\w+)=("[^"]*"|\S+)`)
+
+// LoadBitmapFont carrega uma fonte AngelCode BMFont (.fnt) no formato texto ou XML
+// As páginas PNG referenciadas são procuradas no mesmo diretório do arquivo .fnt.
+// Use TextFont para ativá-la; TextSize escolhe o maior múltiplo inteiro do tamanho
+// original que não ultrapassa o tamanho pedido, mantendo os pixels nítidos.
+func LoadBitmapFont(path string) *Font {
+	defer trackAsset(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao abrir fonte bitmap '%s': %w", path, err))
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	return loadBitmapFont(path, data, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+}
+
+// LoadBitmapFontFS carrega uma fonte AngelCode BMFont de um fs.FS, como um embed.FS
+func LoadBitmapFontFS(fsys fs.FS, name string) *Font {
+	defer trackAsset(name)
+
+	if fsys == nil {
+		reportError(newError(ErrNilArgument, "tentativa de carregar fonte bitmap '%s' de um sistema de arquivos nulo", name))
+		return nil
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao abrir fonte bitmap '%s': %w", name, err))
+		return nil
+	}
+
+	dir := path.Dir(name)
+	return loadBitmapFont(name, data, func(page string) (io.ReadCloser, error) {
+		return fsys.Open(path.Join(dir, page))
+	})
+}
+
+// LoadGridFont carrega uma fonte bitmap a partir de uma imagem dividida em células
+// de tamanho fixo, lidas da esquerda para a direita e de cima para baixo.
+// chars lista os caracteres na ordem das células.
+func LoadGridFont(path string, cellWidth, cellHeight int, chars string) *Font {
+	defer trackAsset(path)
+
+	if cellWidth <= 0 || cellHeight <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões de célula inválidas: %dx%d", cellWidth, cellHeight))
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao abrir fonte bitmap '%s': %w", path, err))
+		return nil
+	}
+	defer file.Close()
+
+	img, err := decodeImage(file)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar fonte bitmap '%s': %w", path, err))
+		return nil
+	}
+
+	bounds := img.Bounds()
+	columns := bounds.Dx() / cellWidth
+	if columns == 0 {
+		reportError(newError(ErrLoadFailed, "imagem da fonte bitmap '%s' é menor que uma célula", path))
+		return nil
+	}
+
+	bf := &bitmapFont{
+		size:       cellHeight,
+		lineHeight: cellHeight,
+		base:       cellHeight,
+		pages:      []*image.Alpha{toAlpha(img)},
+		glyphs:     make(map[rune]bitmapGlyph),
+		kernings:   make(map[[2]rune]int),
+	}
+
+	for i, r := range []rune(chars) {
+		bf.glyphs[r] = bitmapGlyph{
+			x:        (i % columns) * cellWidth,
+			y:        (i / columns) * cellHeight,
+			width:    cellWidth,
+			height:   cellHeight,
+			xadvance: cellWidth,
+		}
+	}
+
+	return &Font{name: path, bitmap: bf, faces: make(map[float64]font.Face)}
+}
+
+// loadBitmapFont interpreta um descritor BMFont e carrega suas páginas com openPage
+func loadBitmapFont(name string, data []byte, openPage func(string) (io.ReadCloser, error)) *Font {
+	tags, err := parseBMFont(data)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar fonte bitmap '%s': %w", name, err))
+		return nil
+	}
+
+	bf := &bitmapFont{
+		glyphs:   make(map[rune]bitmapGlyph),
+		kernings: make(map[[2]rune]int),
+	}
+
+	pageFiles := make(map[int]string)
+	for _, tag := range tags {
+		attr := func(key string) int {
+			v, _ := strconv.Atoi(tag.attrs[key])
+			return v
+		}
+
+		switch tag.name {
+		case "info":
+			bf.size = int(math.Abs(float64(attr("size")))) // Tamanhos negativos indicam altura do caractere
+		case "common":
+			bf.lineHeight = attr("lineHeight")
+			bf.base = attr("base")
+		case "page":
+			pageFiles[attr("id")] = tag.attrs["file"]
+		case "char":
+			bf.glyphs[rune(attr("id"))] = bitmapGlyph{
+				x:        attr("x"),
+				y:        attr("y"),
+				width:    attr("width"),
+				height:   attr("height"),
+				xoffset:  attr("xoffset"),
+				yoffset:  attr("yoffset"),
+				xadvance: attr("xadvance"),
+				page:     attr("page"),
+			}
+		case "kerning":
+			bf.kernings[[2]rune{rune(attr("first")), rune(attr("second"))}] = attr("amount")
+		}
+	}
+
+	if len(bf.glyphs) == 0 || len(pageFiles) == 0 {
+		reportError(newError(ErrLoadFailed, "fonte bitmap '%s' não define caracteres ou páginas", name))
+		return nil
+	}
+	if bf.size == 0 {
+		bf.size = bf.lineHeight
+	}
+
+	bf.pages = make([]*image.Alpha, len(pageFiles))
+	for id, file := range pageFiles {
+		if id < 0 || id >= len(bf.pages) {
+			reportError(newError(ErrLoadFailed, "fonte bitmap '%s' tem página com id inválido: %d", name, id))
+			return nil
+		}
+
+		page, err := loadBitmapFontPage(file, openPage)
+		if err != nil {
+			reportError(newError(ErrLoadFailed, "erro ao carregar página '%s' da fonte bitmap '%s': %w", file, name, err))
+			return nil
+		}
+		bf.pages[id] = page
+	}
+
+	return &Font{name: name, bitmap: bf, faces: make(map[float64]font.Face)}
+}
+
+// loadBitmapFontPage carrega uma página da fonte como máscara alfa
+func loadBitmapFontPage(file string, openPage func(string) (io.ReadCloser, error)) (*image.Alpha, error) {
+	r, err := openPage(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	img, err := decodeImage(r)
+	if err != nil {
+		return nil, err
+	}
+	return toAlpha(img), nil
+}
+
+// bmfontTag é uma linha (formato texto) ou elemento (formato XML) de um descritor BMFont
+type bmfontTag struct {
+	name  string
+	attrs map[string]string
+}
+
+// parseBMFont lê um descritor BMFont detectando o formato texto ou XML
+func parseBMFont(data []byte) ([]bmfontTag, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("BMF")) {
+		return nil, newError(ErrUnsupported, "formato binário do BMFont não é suportado - exporte em texto ou XML")
+	}
+
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return parseBMFontXML(trimmed)
+	}
+
+	var tags []bmfontTag
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		tag := bmfontTag{name: fields[0], attrs: make(map[string]string)}
+		for _, match := range bmfontAttr.FindAllStringSubmatch(line, -1) {
+			tag.attrs[match[1]] = strings.Trim(match[2], `"`)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// parseBMFontXML lê o formato XML do BMFont
+func parseBMFontXML(data []byte) ([]bmfontTag, error) {
+	var tags []bmfontTag
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return tags, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		tag := bmfontTag{name: element.Name.Local, attrs: make(map[string]string)}
+		for _, attr := range element.Attr {
+			tag.attrs[attr.Name.Local] = attr.Value
+		}
+		tags = append(tags, tag)
+	}
+}
+
+// newBasicBitmapFont converte uma face de largura fixa do basicfont em fonte bitmap
+// de tamanho nominal size, desenhada pixel a pixel como a face original na escala 1.
+func newBasicBitmapFont(face *basicfont.Face, size int) *bitmapFont {
+	bf := &bitmapFont{
+		size:       size,
+		lineHeight: face.Height,
+		base:       face.Ascent,
+		pages:      []*image.Alpha{toAlpha(face.Mask)},
+		glyphs:     make(map[rune]bitmapGlyph),
+	}
+	for _, rng := range face.Ranges {
+		for r := rng.Low; r < rng.High; r++ {
+			bf.glyphs[r] = bitmapGlyph{
+				y:        (rng.Offset + int(r-rng.Low)) * face.Height,
+				width:    face.Width,
+				height:   face.Height,
+				xoffset:  face.Left,
+				xadvance: face.Advance,
+			}
+		}
+	}
+	return bf
+}
+
+// toAlpha extrai o canal alfa de uma imagem
+func toAlpha(img image.Image) *image.Alpha {
+	bounds := img.Bounds()
+	alpha := image.NewAlpha(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			alpha.Pix[(y-bounds.Min.Y)*alpha.Stride+(x-bounds.Min.X)] = uint8(a >> 8)
+		}
+	}
+	return alpha
+}
+
+// scaleAlpha amplia uma máscara alfa por um fator inteiro (vizinho mais próximo)
+func scaleAlpha(src *image.Alpha, scale int) *image.Alpha {
+	if scale == 1 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewAlpha(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale))
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			dst.Pix[y*dst.Stride+x] = src.Pix[(y/scale)*src.Stride+x/scale]
+		}
+	}
+	return dst
+}
+
+// scaleFor retorna a escala inteira usada para desenhar a fonte no tamanho pedido
+func (bf *bitmapFont) scaleFor(size float64) int {
+	scale := int(size / float64(bf.size))
+	if scale < 1 {
+		scale = 1
+	}
+	return scale
+}
+
+// bitmapFace implementa font.Face para uma fonte bitmap em uma escala inteira
+type bitmapFace struct {
+	font  *bitmapFont
+	scale int
+	pages []*image.Alpha // Páginas já ampliadas pela escala
+}
+
+// newBitmapFace cria a face de uma fonte bitmap na escala dada
+func newBitmapFace(bf *bitmapFont, scale int) *bitmapFace {
+	face := &bitmapFace{font: bf, scale: scale, pages: make([]*image.Alpha, len(bf.pages))}
+	for i, page := range bf.pages {
+		face.pages[i] = scaleAlpha(page, scale)
+	}
+	return face
+}
+
+// glyph retorna o caractere r ou '?' como substituto para caracteres ausentes
+func (f *bitmapFace) glyph(r rune) (bitmapGlyph, bool) {
+	if g, ok := f.font.glyphs[r]; ok {
+		return g, true
+	}
+	g, ok := f.font.glyphs['?']
+	return g, ok
+}
+
+// Close implementa font.Face
+func (f *bitmapFace) Close() error { return nil }
+
+// Glyph implementa font.Face
+func (f *bitmapFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	g, ok := f.glyph(r)
+	if !ok || g.page < 0 || g.page >= len(f.pages) {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+
+	s := f.scale
+	x := dot.X.Round() + g.xoffset*s
+	y := dot.Y.Round() + (g.yoffset-f.font.base)*s
+	dr := image.Rect(x, y, x+g.width*s, y+g.height*s)
+	return dr, f.pages[g.page], image.Pt(g.x*s, g.y*s), fixed.I(g.xadvance * s), true
+}
+
+// GlyphBounds implementa font.Face
+func (f *bitmapFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	g, ok := f.glyph(r)
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+
+	s := f.scale
+	minX, minY := g.xoffset*s, (g.yoffset-f.font.base)*s
+	bounds := fixed.R(minX, minY, minX+g.width*s, minY+g.height*s)
+	return bounds, fixed.I(g.xadvance * s), true
+}
+
+// GlyphAdvance implementa font.Face
+func (f *bitmapFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	g, ok := f.glyph(r)
+	if !ok {
+		return 0, false
+	}
+	return fixed.I(g.xadvance * f.scale), true
+}
+
+// Kern implementa font.Face
+func (f *bitmapFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return fixed.I(f.font.kernings[[2]rune{r0, r1}] * f.scale)
+}
+
+// Metrics implementa font.Face
+func (f *bitmapFace) Metrics() font.Metrics {
+	s := f.scale
+	return font.Metrics{
+		Height:    fixed.I(f.font.lineHeight * s),
+		Ascent:    fixed.I(f.font.base * s),
+		Descent:   fixed.I((f.font.lineHeight - f.font.base) * s),
+		XHeight:   fixed.I(f.font.base * s / 2),
+		CapHeight: fixed.I(f.font.base * s),
+	}
+}
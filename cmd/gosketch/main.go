@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,31 +17,34 @@ require github.com/Xistaminose/gosketch latest
 `
 
 func printUsage() {
-	fmt.Println("GoSketch - Arte Generativa em Go")
+	fmt.Println(tr("GoSketch - Arte Generativa em Go"))
 	fmt.Println()
-	fmt.Println("Uso:")
-	fmt.Println("  gosketch new <project-name> [template]    Cria um novo projeto GoSketch")
-	fmt.Println("  gosketch list-templates                  Lista templates disponíveis")
-	fmt.Println("  gosketch help                           Exibe esta ajuda")
+	fmt.Println(tr("Uso:"))
+	fmt.Println(tr("  gosketch new <project-name> [template]    Cria um novo projeto GoSketch"))
+	fmt.Println(tr("  gosketch list-templates                  Lista templates disponíveis"))
+	fmt.Println(tr("  gosketch help                           Exibe esta ajuda"))
 	fmt.Println()
-	fmt.Println("Templates disponíveis:")
-	fmt.Println("  basic     - Template básico com uma elipse simples (padrão)")
-	fmt.Println("  rectangle - Template com retângulos e quadrados")
-	fmt.Println("  line      - Template com grade de linhas")
+	fmt.Println(tr("Opções (antes do comando):"))
+	fmt.Println(tr("  -lang <pt-BR|en>    Idioma das mensagens (padrão: o do ambiente)"))
+	fmt.Println()
+	fmt.Println(tr("Templates disponíveis:"))
+	fmt.Println(tr("  basic     - Template básico com uma elipse simples (padrão)"))
+	fmt.Println(tr("  rectangle - Template com retângulos e quadrados"))
+	fmt.Println(tr("  line      - Template com grade de linhas"))
 }
 
 func listTemplates() {
-	fmt.Println("Templates disponíveis para o GoSketch:")
+	fmt.Println(tr("Templates disponíveis para o GoSketch:"))
 	fmt.Println()
-	fmt.Println("  basic     - Template básico com uma elipse simples (padrão)")
-	fmt.Println("  rectangle - Template com retângulos e quadrados")
-	fmt.Println("  line      - Template com grade de linhas")
+	fmt.Println(tr("  basic     - Template básico com uma elipse simples (padrão)"))
+	fmt.Println(tr("  rectangle - Template com retângulos e quadrados"))
+	fmt.Println(tr("  line      - Template com grade de linhas"))
 }
 
 func createNewProject(projectName string, templateName string) error {
 	// Verificar se o nome do projeto é válido
 	if projectName == "" {
-		return fmt.Errorf("Nome de projeto não especificado")
+		return errors.New(tr("Nome de projeto não especificado"))
 	}
 
 	// Verificar template (usar 'basic' se não especificado)
@@ -50,20 +55,20 @@ func createNewProject(projectName string, templateName string) error {
 	// Verificar se o template existe
 	template, ok := templates[templateName]
 	if !ok {
-		return fmt.Errorf("Template '%s' não encontrado. Use 'gosketch list-templates' para ver opções disponíveis", templateName)
+		return fmt.Errorf(tr("Template '%s' não encontrado. Use 'gosketch list-templates' para ver opções disponíveis"), templateName)
 	}
 
 	// Criar diretório para o projeto
 	err := os.Mkdir(projectName, 0755)
 	if err != nil {
-		return fmt.Errorf("Erro ao criar diretório do projeto: %v", err)
+		return fmt.Errorf(tr("Erro ao criar diretório do projeto: %v"), err)
 	}
 
 	// Criar arquivo main.go
 	mainPath := filepath.Join(projectName, "main.go")
-	err = os.WriteFile(mainPath, []byte(template), 0644)
+	err = os.WriteFile(mainPath, []byte(localizeTemplate(template)), 0644)
 	if err != nil {
-		return fmt.Errorf("Erro ao criar arquivo main.go: %v", err)
+		return fmt.Errorf(tr("Erro ao criar arquivo main.go: %v"), err)
 	}
 
 	// Criar go.mod
@@ -76,12 +81,12 @@ func createNewProject(projectName string, templateName string) error {
 	goModPath := filepath.Join(projectName, "go.mod")
 	err = os.WriteFile(goModPath, []byte(goModContent), 0644)
 	if err != nil {
-		return fmt.Errorf("Erro ao criar arquivo go.mod: %v", err)
+		return fmt.Errorf(tr("Erro ao criar arquivo go.mod: %v"), err)
 	}
 
-	fmt.Printf("Projeto '%s' criado com sucesso com o template '%s'!\n", projectName, templateName)
+	fmt.Printf(tr("Projeto '%s' criado com sucesso com o template '%s'!\n"), projectName, templateName)
 	fmt.Println()
-	fmt.Println("Para executar seu projeto:")
+	fmt.Println(tr("Para executar seu projeto:"))
 	fmt.Printf("  cd %s\n", projectName)
 	fmt.Println("  go mod tidy")
 	fmt.Println("  go run main.go")
@@ -90,30 +95,42 @@ func createNewProject(projectName string, templateName string) error {
 }
 
 func main() {
+	// O flag -lang tem precedência sobre o idioma do ambiente
+	lang := flag.String("lang", "", "pt-BR | en")
+	flag.Usage = printUsage
+	flag.Parse()
+	if *lang != "" {
+		if err := setLanguage(*lang); err != nil {
+			fmt.Printf(tr("Erro: %v\n"), err)
+			os.Exit(1)
+		}
+	}
+	args := flag.Args()
+
 	// Verificar argumentos
-	if len(os.Args) < 2 {
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
+	command := args[0]
 
 	switch command {
 	case "new":
-		if len(os.Args) < 3 {
-			fmt.Println("Erro: Nome do projeto não especificado")
+		if len(args) < 2 {
+			fmt.Println(tr("Erro: Nome do projeto não especificado"))
 			fmt.Println()
 			printUsage()
 			os.Exit(1)
 		}
-		projectName := os.Args[2]
+		projectName := args[1]
 		templateName := ""
-		if len(os.Args) >= 4 {
-			templateName = os.Args[3]
+		if len(args) >= 3 {
+			templateName = args[2]
 		}
 		err := createNewProject(projectName, templateName)
 		if err != nil {
-			fmt.Printf("Erro: %v\n", err)
+			fmt.Printf(tr("Erro: %v\n"), err)
 			os.Exit(1)
 		}
 	case "list-templates":
@@ -121,7 +138,7 @@ func main() {
 	case "help":
 		printUsage()
 	default:
-		fmt.Printf("Comando desconhecido: %s\n", command)
+		fmt.Printf(tr("Comando desconhecido: %s\n"), command)
 		fmt.Println()
 		printUsage()
 		os.Exit(1)
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Xistaminose/gosketch/internal/locale"
+)
+
+// language é o idioma das mensagens: o do flag -lang ou, sem ele, o do ambiente
+// (LC_ALL, LC_MESSAGES e LANG), detectado como na biblioteca
+var language = locale.Detect()
+
+// setLanguage define o idioma das mensagens a partir do flag -lang
+func setLanguage(lang string) error {
+	normalized, ok := locale.Normalize(lang)
+	if !ok {
+		return fmt.Errorf(tr("idioma não suportado: '%s' - use pt-BR ou en"), lang)
+	}
+	language = normalized
+	return nil
+}
+
+// tr traduz uma mensagem original em pt-BR para o idioma atual
+func tr(msg string) string {
+	if language == locale.EN {
+		if translated, ok := messagesEN[msg]; ok {
+			return translated
+		}
+	}
+	return msg
+}
+
+// localizeTemplate traduz os comentários de linha de um template
+func localizeTemplate(source string) string {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		code, comment, found := strings.Cut(line, "// ")
+		if found && strings.TrimSpace(code) == "" {
+			lines[i] = code + "// " + tr(comment)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// messagesEN contém as traduções para inglês, indexadas pela mensagem original
+var messagesEN = map[string]string{
+	// Ajuda e templates
+	"GoSketch - Arte Generativa em Go": "GoSketch - Generative Art in Go",
+	"Uso:":                             "Usage:",
+	"  gosketch new <project-name> [template]    Cria um novo projeto GoSketch": "  gosketch new <project-name> [template]    Creates a new GoSketch project",
+	"  gosketch list-templates                  Lista templates disponíveis":    "  gosketch list-templates                  Lists available templates",
+	"  gosketch help                           Exibe esta ajuda":                "  gosketch help                           Shows this help",
+	"Opções (antes do comando):": "Options (before the command):",
+	"  -lang <pt-BR|en>    Idioma das mensagens (padrão: o do ambiente)": "  -lang <pt-BR|en>    Message language (default: from the environment)",
+	"Templates disponíveis:":                                        "Available templates:",
+	"Templates disponíveis para o GoSketch:":                        "Available GoSketch templates:",
+	"  basic     - Template básico com uma elipse simples (padrão)": "  basic     - Basic template with a simple ellipse (default)",
+	"  rectangle - Template com retângulos e quadrados":             "  rectangle - Template with rectangles and squares",
+	"  line      - Template com grade de linhas":                    "  line      - Template with a grid of lines",
+
+	// Criação de projetos
+	"Nome de projeto não especificado": "Project name not specified",
+	"Template '%s' não encontrado. Use 'gosketch list-templates' para ver opções disponíveis": "Template '%s' not found. Use 'gosketch list-templates' to see available options",
+	"Erro ao criar diretório do projeto: %v":                                                  "Error creating project directory: %v",
+	"Erro ao criar arquivo main.go: %v":                                                       "Error creating main.go file: %v",
+	"Erro ao criar arquivo go.mod: %v":                                                        "Error creating go.mod file: %v",
+	"Projeto '%s' criado com sucesso com o template '%s'!\n":                                  "Project '%s' successfully created with the '%s' template!\n",
+	"Para executar seu projeto:":                                                              "To run your project:",
+	"Erro: Nome do projeto não especificado":                                                  "Error: Project name not specified",
+	"Erro: %v\n":                 "Error: %v\n",
+	"Comando desconhecido: %s\n": "Unknown command: %s\n",
+	"idioma não suportado: '%s' - use pt-BR ou en": "unsupported language: '%s' - use pt-BR or en",
+
+	// Comentários dos templates
+	"Desenha um retângulo central": "Draw a centered rectangle",
+	"Desenha um quadrado menor":    "Draw a smaller square",
+	"Desenha uma grade de linhas":  "Draw a grid of lines",
+	"Linhas horizontais":           "Horizontal lines",
+	"Linhas verticais":             "Vertical lines",
+	"Desenha algumas diagonais":    "Draw a few diagonals",
+}
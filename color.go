@@ -10,7 +10,7 @@ type ErrorReporter func(err error)
 
 // Default implementation that simply prints to stdout
 var errorReporter ErrorReporter = func(err error) {
-	fmt.Printf("ERRO: %v\n", err)
+	fmt.Printf(tr("ERRO: %v\n"), err)
 }
 
 // SetColorErrorReporter sets the error reporter function for color-related errors
@@ -48,13 +48,13 @@ func ParseColorValue(c ColorValue) color.Color {
 		// Converte int para uint8 se estiver no range correto
 		if v < 0 || v > 255 {
 			// Para erro em valores fora do range, retorna branco
-			errorReporter(fmt.Errorf("valor int fora do range para cor: %d - deve estar entre 0-255", v))
+			errorReporter(newError(ErrInvalidColor, "valor int fora do range para cor: %d - deve estar entre 0-255", v))
 			return color.White
 		}
 		return color.RGBA{R: uint8(v), G: uint8(v), B: uint8(v), A: 255}
 	default:
 		// Para tipos inválidos, retorna branco
-		errorReporter(fmt.Errorf("tipo de cor inválido dentro de ColorValue: %T", c.value))
+		errorReporter(newError(ErrInvalidColor, "tipo de cor inválido dentro de ColorValue: %T", c.value))
 		return color.White
 	}
 }
@@ -0,0 +1,61 @@
+/*
+Projeto: GoSketch - Concorrência
+Descrição: Modelo de concorrência da biblioteca e fila de comandos de desenho.
+
+O estado de desenho (canvas, cores, fonte, transformações, pixels e callbacks) pertence
+à goroutine que chama Run: setup, update, draw e os callbacks de eventos rodam nela e
+podem usar toda a API livremente. As demais goroutines podem:
+  - carregar assets (LoadImage*, LoadAnimation*, LoadFont*, LoadBitmapFont*, LoadGridFont),
+    inclusive em paralelo, e usar o cache de imagens;
+  - calcular geometria sem desenhar (shapes.Create*, PolylinePath, BezierPath,
+    CirclePath e Path.PointAt);
+  - reportar erros, chamar Exit/ExitWithError e enfileirar desenhos com QueueDraw.
+TextToPoints e TextToShape leem a fonte e o alinhamento de texto atuais e, como as
+funções de desenho, devem ser chamadas na goroutine principal (ou por QueueDraw).
+O mesmo vale para RecordDisplayList e RenderTiled: enquanto gravam, todas as formas e
+fundos desenhados vão para a lista em gravação, inclusive os do loop principal, e o
+estilo usado é o estado de desenho global. Em outras goroutines, envie a gravação com
+QueueDraw e espere a lista; Rasterize e RasterizeTiled podem rodar em qualquer goroutine.
+Qualquer outra função de desenho chamada fora da goroutine principal é uma condição de
+corrida; envie-a pela fila com QueueDraw.
+*/
+
+package gosketch
+
+import "sync"
+
+// Estado global compartilhado entre goroutines
+var (
+	reportMu sync.Mutex // Protege errorHandler
+
+	drawQueueMu sync.Mutex
+	drawQueue   []func()
+)
+
+// QueueDraw enfileira uma operação de desenho enviada por qualquer goroutine
+// As operações são executadas na goroutine principal, na ordem de envio, logo
+// após a função draw do próximo quadro (ou no próximo quadro da tela, se o loop
+// estiver parado com NoLoop), de modo que o resultado aparece sobre o desenho de draw.
+func QueueDraw(f func()) {
+	if f == nil {
+		reportError(newError(ErrNilArgument, "tentativa de enfileirar um comando de desenho nulo"))
+		return
+	}
+
+	drawQueueMu.Lock()
+	defer drawQueueMu.Unlock()
+	drawQueue = append(drawQueue, f)
+}
+
+// runDrawQueue executa os comandos enfileirados até o momento
+// Comandos enfileirados durante a execução ficam para o próximo quadro.
+func runDrawQueue() {
+	drawQueueMu.Lock()
+	queue := drawQueue
+	drawQueue = nil
+	drawQueueMu.Unlock()
+
+	for _, f := range queue {
+		callHandler("QueueDraw", f)
+	}
+}
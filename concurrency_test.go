@@ -0,0 +1,197 @@
+package gosketch
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// captureErrors instala um tratador que conta os erros reportados e restaura o
+// tratador padrão, o limite de repetição e o estado de encerramento ao final do teste
+func captureErrors(t *testing.T, handler func(error)) {
+	t.Helper()
+	interval := errorRepeatInterval
+	SetErrorRateLimit(0)
+	SetErrorHandler(handler)
+	t.Cleanup(func() {
+		SetErrorHandler(nil)
+		SetErrorRateLimit(interval)
+		resetRunState()
+	})
+}
+
+// writeTestPNG grava uma imagem PNG de w x h pixels e retorna o caminho e o conteúdo
+func writeTestPNG(t *testing.T, w, h int) (string, []byte) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "test.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, buf.Bytes()
+}
+
+func TestParallelLoadImage(t *testing.T) {
+	var errs atomic.Int32
+	captureErrors(t, func(err error) {
+		errs.Add(1)
+		t.Error(err)
+	})
+	t.Cleanup(ClearImageCache)
+
+	path, data := writeTestPNG(t, 8, 6)
+	SetImageCacheLimit(8 * 6 * 4 * 2)
+	t.Cleanup(func() { SetImageCacheLimit(0) })
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				var img *SketchImage
+				switch i % 4 {
+				case 0, 1:
+					img = LoadImage(path)
+				case 2:
+					img = LoadImageBytes(data)
+				case 3:
+					UnloadImage(path)
+					ImageCacheUsage()
+					continue
+				}
+				if img == nil || img.Width() != 8 || img.Height() != 6 {
+					t.Errorf("imagem carregada inválida: %+v", img)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count, used := ImageCacheUsage(); count > 1 || used > 8*6*4 {
+		t.Errorf("cache com %d imagens e %d bytes; esperado no máximo a imagem de %q", count, used, path)
+	}
+	if errs.Load() != 0 {
+		t.Errorf("%d erros reportados", errs.Load())
+	}
+}
+
+func TestQueueDrawOrder(t *testing.T) {
+	const goroutines, perGoroutine = 8, 100
+
+	// Os comandos rodam em runDrawQueue, na goroutine do teste, então o slice
+	// não precisa de lock
+	var executed [][2]int
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				QueueDraw(func() { executed = append(executed, [2]int{g, i}) })
+			}
+		}()
+	}
+	wg.Wait()
+	runDrawQueue()
+
+	if len(executed) != goroutines*perGoroutine {
+		t.Fatalf("%d comandos executados; esperado %d", len(executed), goroutines*perGoroutine)
+	}
+	next := make([]int, goroutines)
+	for _, cmd := range executed {
+		g, i := cmd[0], cmd[1]
+		if i != next[g] {
+			t.Fatalf("goroutine %d: comando %d executado antes do %d", g, i, next[g])
+		}
+		next[g]++
+	}
+}
+
+func TestQueueDrawDuringRun(t *testing.T) {
+	var order []string
+	QueueDraw(func() {
+		order = append(order, "primeiro")
+		QueueDraw(func() { order = append(order, "segundo") })
+	})
+
+	// Comandos enfileirados durante a execução ficam para o próximo quadro
+	runDrawQueue()
+	if len(order) != 1 {
+		t.Fatalf("após o primeiro quadro: %q", order)
+	}
+	runDrawQueue()
+	if len(order) != 2 || order[1] != "segundo" {
+		t.Fatalf("após o segundo quadro: %q", order)
+	}
+}
+
+func TestConcurrentReportErrorAndExit(t *testing.T) {
+	var handled atomic.Int32
+	captureErrors(t, func(error) { handled.Add(1) })
+
+	const goroutines, perGoroutine = 8, 50
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				reportError(newError(ErrInvalidArgument, "erro de teste %d/%d", g, i))
+				if i == perGoroutine/2 {
+					Exit()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := handled.Load(); got != goroutines*perGoroutine {
+		t.Errorf("tratador chamado %d vezes; esperado %d", got, goroutines*perGoroutine)
+	}
+	if !exitPending() || exitError() != nil {
+		t.Errorf("exitPending = %v, exitError = %v; esperado encerramento sem erro", exitPending(), exitError())
+	}
+}
+
+func TestErrorHandlerCanReportErrors(t *testing.T) {
+	var handled atomic.Int32
+	captureErrors(t, func(err error) {
+		// Um tratador que usa a API pode reportar outro erro sem travar
+		if handled.Add(1) == 1 {
+			reportError(newError(ErrInvalidArgument, "erro dentro do tratador"))
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		reportError(newError(ErrInvalidArgument, "erro original"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reportError travou ao ser chamada pelo tratador de erros")
+	}
+	if got := handled.Load(); got != 2 {
+		t.Errorf("tratador chamado %d vezes; esperado 2", got)
+	}
+}
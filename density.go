@@ -0,0 +1,127 @@
+/*
+Projeto: GoSketch - Densidade de Pixels
+Descrição: Suporte a telas de alta densidade (HiDPI): o canvas é mantido em um buffer
+de Width*d x Height*d pixels físicos, enquanto as coordenadas do sketch continuam lógicas
+*/
+
+package gosketch
+
+import (
+	"image/color"
+
+	"github.com/Xistaminose/gosketch/shapes"
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// pixelDensity é a densidade usada ao criar o canvas
+var pixelDensity float64 = 1
+
+// PixelDensity define quantos pixels físicos correspondem a um pixel lógico do canvas
+// Use PixelDensity(DisplayDensity()) para desenhar com nitidez em telas HiDPI.
+// Se o canvas já existir, seu buffer é recriado na nova densidade, preservando o conteúdo.
+// Similar à função pixelDensity() do p5.js
+func PixelDensity(d float64) {
+	if d <= 0 {
+		reportError(newError(ErrInvalidArgument, "densidade de pixels inválida: %.2f - a densidade deve ser positiva", d))
+		return
+	}
+	pixelDensity = d
+
+	if canvas == nil || canvas.density == d {
+		return
+	}
+
+	if IsRecording() {
+		reportError(newError(ErrRecording, "não é possível alterar a densidade de pixels durante uma gravação - use StopRecording antes"))
+		return
+	}
+
+	img := ebiten.NewImage(physicalSize(canvas.Width, d), physicalSize(canvas.Height, d))
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(d/canvas.density, d/canvas.density)
+	opts.Filter = ebiten.FilterLinear
+	img.DrawImage(canvas.img, opts)
+	canvas.img.Deallocate()
+	canvas.img = img
+	canvas.density = d
+
+	// O array de pixels se refere à resolução antiga
+	pixels = nil
+}
+
+// GetPixelDensity retorna a densidade de pixels atual do canvas
+func GetPixelDensity() float64 {
+	if canvas != nil {
+		return canvas.density
+	}
+	return pixelDensity
+}
+
+// DisplayDensity retorna a densidade de pixels do monitor atual
+// (por exemplo, 2 em telas Retina)
+// Similar à função displayDensity() do p5.js
+func DisplayDensity() float64 {
+	if m := ebiten.Monitor(); m != nil {
+		return m.DeviceScaleFactor()
+	}
+	return 1
+}
+
+// physicalSize converte uma dimensão lógica em pixels físicos
+func physicalSize(logical int, density float64) int {
+	size := int(float64(logical)*density + 0.5)
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// physicalShape escala a forma para os pixels físicos do canvas
+// Formas que não implementam shapes.Transformable são desenhadas em um canvas
+// que amplia cada pixel, preservando a posição mas não a nitidez.
+func physicalShape(s shapes.Shape) (shapes.Shape, shapes.Canvas) {
+	if canvas.density == 1 {
+		return s, canvas
+	}
+	if scaled, ok := shapes.Transform(s, canvas.density, 0, 0); ok {
+		return scaled, canvas
+	}
+	return s, &densityCanvas{canvas}
+}
+
+// densityCanvas adapta o canvas a formas desenhadas em coordenadas lógicas,
+// pintando cada pixel lógico como um bloco de pixels físicos
+type densityCanvas struct {
+	*Canvas
+}
+
+// Set implementa shapes.Canvas em coordenadas lógicas
+func (c *densityCanvas) Set(x, y int, clr color.Color) {
+	d := c.density
+	x0, y0 := int(float64(x)*d), int(float64(y)*d)
+	x1, y1 := int(float64(x+1)*d), int(float64(y+1)*d)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			c.Canvas.Set(px, py, clr)
+		}
+	}
+}
+
+// GetWidth implementa shapes.Canvas retornando a largura lógica
+func (c *densityCanvas) GetWidth() int { return c.Width }
+
+// GetHeight implementa shapes.Canvas retornando a altura lógica
+func (c *densityCanvas) GetHeight() int { return c.Height }
+
+// textDrawFace retorna a face usada para desenhar texto nos pixels físicos do canvas
+// As medidas de layout continuam usando a face lógica (currentFont).
+func textDrawFace() font.Face {
+	if canvas == nil || canvas.density == 1 {
+		return currentFont
+	}
+	if face := textFont.face(textSize * canvas.density); face != nil {
+		return face
+	}
+	return currentFont
+}
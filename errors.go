@@ -0,0 +1,187 @@
+/*
+Projeto: GoSketch - Erros
+Descrição: Erros sentinela exportados para uso com errors.Is, deduplicação e limitação
+da frequência de erros repetidos e modo estrito, que transforma qualquer erro reportado
+em falha de Run
+*/
+
+package gosketch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Categorias de erro reportadas pela biblioteca
+// Todo erro enviado ao tratador de erros satisfaz errors.Is com uma delas.
+// As mensagens seguem o idioma de SetLanguage.
+var (
+	ErrNoCanvas          = newErrorKind("canvas não inicializado")
+	ErrInvalidDimensions = newErrorKind("dimensões inválidas")
+	ErrInvalidColor      = newErrorKind("cor inválida")
+	ErrInvalidArgument   = newErrorKind("argumento inválido")
+	ErrNilArgument       = newErrorKind("argumento nulo")
+	ErrOutOfBounds       = newErrorKind("coordenadas fora dos limites")
+	ErrNoFont            = newErrorKind("fonte indisponível")
+	ErrLoadFailed        = newErrorKind("falha ao carregar asset")
+	ErrWriteFailed       = newErrorKind("falha ao escrever arquivo")
+	ErrUnsupported       = newErrorKind("formato não suportado")
+	ErrInvalidState      = newErrorKind("operação inválida no estado atual")
+	ErrRecording         = newErrorKind("erro de gravação")
+	ErrPanic             = newErrorKind("pânico no código do sketch")
+)
+
+// errorKind é uma categoria de erro com mensagem traduzida conforme o idioma atual
+type errorKind struct {
+	msg string
+}
+
+// Error retorna a descrição da categoria no idioma atual
+func (k *errorKind) Error() string {
+	return tr(k.msg)
+}
+
+// newErrorKind cria uma categoria de erro com a mensagem original em pt-BR
+func newErrorKind(msg string) error {
+	return &errorKind{msg: msg}
+}
+
+// SketchError é um erro da biblioteca com sua categoria
+// A mensagem é a mesma do erro original; errors.Is reconhece tanto a categoria
+// quanto a causa (por exemplo, os.ErrNotExist ao abrir um arquivo).
+type SketchError struct {
+	Kind error // Uma das variáveis Err* deste pacote
+	Err  error // Erro com a mensagem detalhada e a causa, se houver
+}
+
+// Error retorna a mensagem detalhada do erro
+func (e *SketchError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap permite que errors.Is e errors.As encontrem a categoria e a causa
+func (e *SketchError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// newError cria um SketchError da categoria kind com a mensagem formatada,
+// traduzida para o idioma atual. Use %w no formato para preservar a causa.
+func newError(kind error, format string, args ...any) error {
+	return &SketchError{Kind: kind, Err: fmt.Errorf(tr(format), args...)}
+}
+
+// repeatedError é um erro reportado novamente após ter sido suprimido
+type repeatedError struct {
+	err        error
+	suppressed int
+}
+
+// Error retorna a mensagem original com o número de repetições suprimidas
+func (e *repeatedError) Error() string {
+	return fmt.Sprintf(tr("%v (repetido mais %d vezes desde o último aviso)"), e.err, e.suppressed)
+}
+
+// Unwrap retorna o erro original
+func (e *repeatedError) Unwrap() error {
+	return e.err
+}
+
+// errorEntry acompanha as ocorrências de uma mensagem de erro
+type errorEntry struct {
+	reportedAt time.Time
+	suppressed int
+}
+
+// maxTrackedErrors limita quantas mensagens distintas são acompanhadas
+const maxTrackedErrors = 1024
+
+// Estado global de tratamento de erros
+// errorMu protege o acompanhamento de mensagens, pois erros podem ser reportados
+// por goroutines que carregam assets.
+var (
+	errorMu             sync.Mutex
+	errorRepeatInterval = 5 * time.Second // Intervalo mínimo entre avisos da mesma mensagem
+	trackedErrors       = make(map[string]*errorEntry)
+	strictMode          bool
+)
+
+// SetErrorRateLimit define o intervalo mínimo entre dois avisos da mesma mensagem de erro
+// Repetições dentro do intervalo são contadas e informadas no aviso seguinte, o que
+// evita inundar o log quando um erro acontece a cada quadro. Com 0, todo erro é reportado.
+func SetErrorRateLimit(interval time.Duration) {
+	errorMu.Lock()
+	defer errorMu.Unlock()
+
+	if interval < 0 {
+		interval = 0
+	}
+	errorRepeatInterval = interval
+	trackedErrors = make(map[string]*errorEntry)
+}
+
+// StrictMode faz com que qualquer erro reportado encerre o sketch, com Run
+// retornando o erro. Útil em testes, para que erros não passem despercebidos.
+func StrictMode() {
+	errorMu.Lock()
+	defer errorMu.Unlock()
+	strictMode = true
+}
+
+// NoStrictMode desativa o modo estrito: erros voltam a ser apenas reportados
+// Em testes, use-a com t.Cleanup para que o modo não afete os testes seguintes.
+func NoStrictMode() {
+	errorMu.Lock()
+	defer errorMu.Unlock()
+	strictMode = false
+}
+
+// throttleError decide se o erro deve ser entregue ao tratador agora,
+// retornando o erro a entregar (com a contagem de repetições, se houver)
+func throttleError(err error) (error, bool) {
+	errorMu.Lock()
+	defer errorMu.Unlock()
+
+	if errorRepeatInterval <= 0 {
+		return err, true
+	}
+
+	now := time.Now()
+	key := err.Error()
+	entry, exists := trackedErrors[key]
+	if !exists {
+		if len(trackedErrors) >= maxTrackedErrors {
+			trackedErrors = make(map[string]*errorEntry)
+		}
+		trackedErrors[key] = &errorEntry{reportedAt: now}
+		return err, true
+	}
+
+	if now.Sub(entry.reportedAt) < errorRepeatInterval {
+		entry.suppressed++
+		return nil, false
+	}
+
+	entry.reportedAt = now
+	if entry.suppressed > 0 {
+		err = &repeatedError{err: err, suppressed: entry.suppressed}
+		entry.suppressed = 0
+	}
+	return err, true
+}
+
+// failStrict encerra o sketch com o erro quando o modo estrito está ativo
+func failStrict(err error) {
+	errorMu.Lock()
+	strict := strictMode
+	errorMu.Unlock()
+
+	if strict {
+		exitMu.Lock()
+		defer exitMu.Unlock()
+		if exitErr == nil {
+			exitRequested = true
+			exitErr = fmt.Errorf(tr("modo estrito: %w"), err)
+		}
+	}
+}
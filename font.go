@@ -0,0 +1,198 @@
+/*
+Projeto: GoSketch - Fontes
+Descrição: Carregamento de fontes TrueType/OpenType e criação de faces por tamanho,
+usadas por Text, TextSize e TextFont (fontes bitmap ficam em bitmapfont.go)
+*/
+
+package gosketch
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// Font representa uma fonte carregada: vetorial (TrueType/OpenType) ou bitmap
+// As faces de cada tamanho são criadas sob demanda e mantidas em cache.
+type Font struct {
+	name   string
+	sfnt   *opentype.Font // Contornos da fonte vetorial
+	bitmap *bitmapFont    // Dados da fonte bitmap
+	faces  map[float64]font.Face
+	recent []float64 // Tamanhos em cache, do usado há mais tempo ao mais recente
+}
+
+// maxCachedFaces limita quantas faces cada fonte mantém em cache
+// Animar TextSize cria uma face por tamanho; as usadas há mais tempo são descartadas.
+const maxCachedFaces = 16
+
+// Estado global de fonte
+var (
+	// A fonte padrão é a basicfont 7x13, com tamanho nominal 12 (o TextSize padrão):
+	// como fonte bitmap, TextSize a amplia em múltiplos inteiros
+	defaultFont = &Font{
+		name:   "basicfont 7x13",
+		bitmap: newBasicBitmapFont(basicfont.Face7x13, 12),
+		faces:  make(map[float64]font.Face),
+	}
+	defaultOutlineFont = mustParseFont("Go Regular", goregular.TTF) // Contornos do texto com a fonte padrão
+	textFont           = defaultFont                                // Fonte usada por Text
+)
+
+// LoadFont carrega uma fonte TrueType (.ttf) ou OpenType (.otf) do sistema de arquivos
+// Em coleções (.ttc/.otc), a primeira fonte é usada.
+func LoadFont(path string) *Font {
+	defer trackAsset(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao abrir fonte '%s': %w", path, err))
+		return nil
+	}
+	return loadFont(path, data)
+}
+
+// LoadFontFS carrega uma fonte TrueType/OpenType de um fs.FS, como um embed.FS
+func LoadFontFS(fsys fs.FS, name string) *Font {
+	defer trackAsset(name)
+
+	if fsys == nil {
+		reportError(newError(ErrNilArgument, "tentativa de carregar fonte '%s' de um sistema de arquivos nulo", name))
+		return nil
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao abrir fonte '%s': %w", name, err))
+		return nil
+	}
+	return loadFont(name, data)
+}
+
+// LoadFontBytes carrega uma fonte TrueType/OpenType a partir de seu conteúdo em memória
+func LoadFontBytes(data []byte) *Font {
+	if len(data) == 0 {
+		reportError(newError(ErrNilArgument, "tentativa de carregar fonte de dados vazios"))
+		return nil
+	}
+	return loadFont("", data)
+}
+
+// loadFont interpreta os dados de uma fonte ou coleção de fontes
+func loadFont(name string, data []byte) *Font {
+	collection, err := opentype.ParseCollection(data)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar fonte '%s': %w", name, err))
+		return nil
+	}
+
+	if collection.NumFonts() == 0 {
+		reportError(newError(ErrLoadFailed, "arquivo de fonte '%s' não contém fontes", name))
+		return nil
+	}
+
+	sfnt, err := collection.Font(0)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar fonte '%s': %w", name, err))
+		return nil
+	}
+
+	return &Font{name: name, sfnt: sfnt, faces: make(map[float64]font.Face)}
+}
+
+// mustParseFont carrega uma fonte embutida na biblioteca
+func mustParseFont(name string, data []byte) *Font {
+	sfnt, err := opentype.Parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("fonte embutida '%s' inválida: %v", name, err))
+	}
+	return &Font{name: name, sfnt: sfnt, faces: make(map[float64]font.Face)}
+}
+
+// face retorna a face da fonte no tamanho especificado (em pixels), criando-a se necessário
+func (f *Font) face(size float64) font.Face {
+	// Fontes bitmap usam escalas inteiras: tamanhos com a mesma escala compartilham a face
+	if f.bitmap != nil {
+		size = float64(f.bitmap.scaleFor(size))
+	}
+	if face, exists := f.faces[size]; exists {
+		f.touchFace(size)
+		return face
+	}
+
+	if f.bitmap != nil {
+		face := newBitmapFace(f.bitmap, int(size))
+		f.cacheFace(size, face)
+		return face
+	}
+
+	face, err := opentype.NewFace(f.sfnt, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72, // Com 72 DPI, o tamanho em pontos corresponde ao tamanho em pixels
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao criar face da fonte '%s' no tamanho %.2f: %w", f.name, size, err))
+		return nil
+	}
+
+	f.cacheFace(size, face)
+	return face
+}
+
+// cacheFace guarda a face do tamanho size, descartando a usada há mais tempo
+// se o cache estiver cheio
+func (f *Font) cacheFace(size float64, face font.Face) {
+	if len(f.recent) >= maxCachedFaces {
+		delete(f.faces, f.recent[0])
+		f.recent = f.recent[1:]
+	}
+	f.faces[size] = face
+	f.recent = append(f.recent, size)
+}
+
+// touchFace marca o tamanho size como o usado mais recentemente
+func (f *Font) touchFace(size float64) {
+	if i := slices.Index(f.recent, size); i >= 0 {
+		f.recent = append(slices.Delete(f.recent, i, i+1), size)
+	}
+}
+
+// IsBitmap retorna se a fonte é uma fonte bitmap (sem contornos vetoriais)
+func (f *Font) IsBitmap() bool {
+	return f.bitmap != nil
+}
+
+// Name retorna o nome do arquivo de onde a fonte foi carregada
+func (f *Font) Name() string {
+	return f.name
+}
+
+// TextFont define a fonte usada por Text e, opcionalmente, o tamanho do texto
+// Se f for nil, volta para a fonte padrão.
+// Similar à função textFont() do p5.js/Processing
+func TextFont(f *Font, size ...float64) {
+	if f == nil {
+		f = defaultFont
+	}
+	textFont = f
+
+	if len(size) > 0 {
+		TextSize(size[0])
+		return
+	}
+	updateTextFace()
+}
+
+// updateTextFace atualiza a face atual a partir da fonte e do tamanho de texto
+func updateTextFace() {
+	if face := textFont.face(textSize); face != nil {
+		currentFont = face
+	}
+}
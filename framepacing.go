@@ -0,0 +1,121 @@
+/*
+Projeto: GoSketch - Ritmo de Quadros
+Descrição: Separa a atualização em passo fixo (Update, na taxa de UpdateRate) da
+renderização (draw, na taxa de FrameRate), sem bloquear a thread de renderização,
+com taxa de quadros suavizada, contagem de quadros, deltaTime e quadros perdidos
+*/
+
+package gosketch
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// frameRateSmoothing é o peso de cada novo intervalo na média móvel da taxa de quadros
+const frameRateSmoothing = 0.1
+
+// Estado global do ritmo de quadros
+var (
+	updateFn      func()
+	updateRate    = 60 // Atualizações por segundo do passo fixo
+	frameCount    int
+	deltaTime     float64 // Milissegundos entre os dois últimos quadros desenhados
+	droppedFrames int
+	lastDrawAt    time.Time // Momento do último quadro desenhado
+	nextDrawAt    time.Time // Momento previsto para o próximo quadro
+)
+
+// Update registra uma função chamada em passo fixo, UpdateRate vezes por segundo,
+// independentemente da taxa de renderização. Use-a para física e simulações que
+// precisam ser determinísticas; draw deve apenas desenhar o estado atual.
+func Update(f func()) {
+	updateFn = f
+}
+
+// UpdateRate define quantas vezes por segundo Update é chamada (padrão 60)
+// A entrada (mouse e teclado) também é processada nesse ritmo.
+func UpdateRate(tps int) {
+	if tps <= 0 {
+		reportError(newError(ErrInvalidArgument, "taxa de atualização inválida: %d - a taxa deve ser positiva", tps))
+		return
+	}
+	updateRate = tps
+	ebiten.SetTPS(tps)
+}
+
+// GetUpdateRate retorna quantas vezes por segundo Update é chamada
+func GetUpdateRate() int {
+	return updateRate
+}
+
+// FrameCount retorna o número de quadros desenhados desde o início do sketch
+// Similar à variável frameCount do p5.js/Processing
+func FrameCount() int {
+	return frameCount
+}
+
+// DeltaTime retorna o tempo, em milissegundos, entre o quadro anterior e o atual
+// Similar à variável deltaTime do p5.js
+func DeltaTime() float64 {
+	return deltaTime
+}
+
+// DroppedFrames retorna quantos quadros deixaram de ser desenhados no prazo
+// porque o quadro anterior (ou o sistema) demorou mais que o intervalo de FrameRate
+func DroppedFrames() int {
+	return droppedFrames
+}
+
+// frameDue informa se um novo quadro deve ser desenhado agora, de acordo com FrameRate
+// Nunca bloqueia: quando o quadro ainda não é devido, a tela apenas reapresenta o canvas.
+func frameDue(now time.Time) bool {
+	if targetFPS <= 0 || nextDrawAt.IsZero() {
+		return true
+	}
+
+	interval := time.Second / time.Duration(targetFPS)
+
+	// Tolerância para a variação do intervalo de sincronia vertical
+	return !now.Before(nextDrawAt.Add(-interval / 4))
+}
+
+// beginFrame registra um quadro desenhado: avança o agendamento, conta quadros
+// perdidos e atualiza deltaTime e a taxa de quadros suavizada
+func beginFrame(now time.Time) {
+	if targetFPS > 0 {
+		interval := time.Second / time.Duration(targetFPS)
+		if nextDrawAt.IsZero() {
+			nextDrawAt = now
+		}
+		// Atrasado mais de um intervalo: os quadros intermediários são perdidos
+		// e o agendamento é realinhado em vez de tentar recuperá-los
+		if behind := now.Sub(nextDrawAt); behind > interval {
+			droppedFrames += int(behind / interval)
+			nextDrawAt = now
+		}
+		nextDrawAt = nextDrawAt.Add(interval)
+	}
+
+	if !lastDrawAt.IsZero() {
+		elapsed := now.Sub(lastDrawAt).Seconds()
+		deltaTime = elapsed * 1000
+		if elapsed > 0 {
+			if frameRate == 0 {
+				frameRate = 1 / elapsed
+			} else {
+				frameRate += frameRateSmoothing * (1/elapsed - frameRate)
+			}
+		}
+	}
+	lastDrawAt = now
+	frameCount++
+}
+
+// resetFramePacing reinicia o agendamento, por exemplo após Loop ou Redraw,
+// para que o tempo parado não seja contado como quadros perdidos
+func resetFramePacing() {
+	nextDrawAt = time.Time{}
+	lastDrawAt = time.Time{}
+}
@@ -4,14 +4,17 @@ go 1.23.0
 
 toolchain go1.23.9
 
-require github.com/hajimehoshi/ebiten/v2 v2.8.2
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.8.2
+	golang.org/x/image v0.27.0
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.8.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	golang.org/x/image v0.27.0 // indirect
-	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
 )
@@ -0,0 +1,50 @@
+/*
+Projeto: GoSketch - Idiomas
+Descrição: Catálogo de mensagens em pt-BR (idioma original das mensagens) e en,
+escolhido por SetLanguage ou pelas variáveis de ambiente LC_ALL, LC_MESSAGES e LANG
+*/
+
+package gosketch
+
+import "github.com/Xistaminose/gosketch/internal/locale"
+
+// Idiomas suportados pelo catálogo de mensagens
+const (
+	LANG_PT_BR = locale.PTBR
+	LANG_EN    = locale.EN
+)
+
+// catalogs relaciona cada idioma às traduções das mensagens originais em pt-BR
+// As mensagens em pt-BR são usadas como identificadores, no estilo do gettext.
+var catalogs = map[string]map[string]string{
+	LANG_PT_BR: nil,
+	LANG_EN:    messagesEN,
+}
+
+// language é o idioma atual das mensagens
+var language = locale.Detect()
+
+// SetLanguage define o idioma das mensagens de erro: "pt-BR" ou "en"
+// Variantes como "pt", "pt_BR.UTF-8" ou "en-US" também são aceitas.
+func SetLanguage(lang string) {
+	normalized, ok := locale.Normalize(lang)
+	if !ok {
+		reportError(newError(ErrInvalidArgument, "idioma não suportado: '%s' - use pt-BR ou en", lang))
+		return
+	}
+	language = normalized
+}
+
+// Language retorna o idioma atual das mensagens
+func Language() string {
+	return language
+}
+
+// tr traduz uma mensagem original em pt-BR para o idioma atual
+// Mensagens sem tradução são retornadas sem alteração.
+func tr(msg string) string {
+	if translated, ok := catalogs[language][msg]; ok {
+		return translated
+	}
+	return msg
+}
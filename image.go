@@ -1,146 +1,367 @@
 /*
 Projeto: GoSketch - Funções de Imagem
 Descrição: Implementação de funções para manipulação de imagens inspiradas em p5.js
-Inclui: loadImage(), image(), getPixel(), setPixel(), text() e pixels[]
+Inclui: loadImage() (arquivo, fs.FS, io.Reader e bytes), image(), getPixel(), setPixel(), get(), copy(), tint(),
+imageMode() e pixels[]
 */
 
 package gosketch
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/binary"
 	"image"
 	"image/color"
+	_ "image/gif" // registra o decodificador GIF
 	"image/jpeg"
 	"image/png"
+	"io"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
+	_ "golang.org/x/image/bmp"  // registra o decodificador BMP
+	_ "golang.org/x/image/tiff" // registra o decodificador TIFF
+	_ "golang.org/x/image/webp" // registra o decodificador WebP
 )
 
 // Estrutura para representar uma imagem carregada
 type SketchImage struct {
-	img    *ebiten.Image
-	width  int
-	height int
+	img      *ebiten.Image
+	width    int
+	height   int
+	path     string      // Caminho no cache de imagens; vazio fora do cache
+	released atomic.Bool // Memória liberada pelo cache; recarregada no próximo uso
+	reloadMu sync.Mutex  // Garante que apenas uma goroutine recarregue a imagem
 }
 
-// Variáveis globais para gerenciamento de imagens e texto
+// ImageSource é implementado pelos tipos que podem ser desenhados por Image:
+// *SketchImage e *Animation (que fornece o quadro atual da reprodução)
+type ImageSource interface {
+	sketchImage() *SketchImage
+}
+
+// Modos de posicionamento usados por ImageMode
+// Similar às constantes CORNER, CORNERS e CENTER do p5.js/Processing
+const (
+	CORNER  = iota // x, y é o canto superior esquerdo; dimensões são largura e altura
+	CORNERS        // x, y é um canto; dimensões são as coordenadas do canto oposto
+	CENTER         // x, y é o centro; dimensões são largura e altura
+)
+
+// Variáveis globais para gerenciamento de imagens
 var (
-	loadedImages map[string]*SketchImage = make(map[string]*SketchImage)
-	currentFont  font.Face               = basicfont.Face7x13
-	textSize     float64                 = 12
-	textColor    color.Color             = color.Black
-	pixels       [][]color.Color         // Array 2D para acesso aos pixels
+	loadedImages *imageCache     = newImageCache()
+	pixels       [][]color.Color // Array 2D para acesso aos pixels
+	imageMode    int             = CORNER
+	tintColor    color.Color     = color.White
+	tintEnabled  bool            = false
 )
 
 // LoadImage carrega uma imagem do sistema de arquivos
+// As imagens ficam em cache por caminho (veja UnloadImage e SetImageCacheLimit).
+// O formato é detectado pelo conteúdo do arquivo (PNG, JPEG, GIF, BMP, TIFF ou WebP),
+// independente da extensão
 func LoadImage(path string) *SketchImage {
+	defer trackAsset(path)
+
 	// Verifica se a imagem já foi carregada
-	if img, exists := loadedImages[path]; exists {
+	if img := loadedImages.get(path); img != nil {
 		return img
 	}
 
 	// Abre o arquivo
 	file, err := os.Open(path)
 	if err != nil {
-		reportError(fmt.Errorf("erro ao abrir imagem '%s': %v", path, err))
+		reportError(newError(ErrLoadFailed, "erro ao abrir imagem '%s': %w", path, err))
 		return nil
 	}
 	defer file.Close()
 
-	// Decodifica a imagem baseado na extensão
-	var img image.Image
-	ext := strings.ToLower(filepath.Ext(path))
+	info, err := file.Stat()
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao ler informações da imagem '%s': %w", path, err))
+		return nil
+	}
 
-	switch ext {
-	case ".png":
-		img, err = png.Decode(file)
-	case ".jpg", ".jpeg":
-		img, err = jpeg.Decode(file)
-	default:
-		// Tenta decodificar automaticamente
-		img, _, err = image.Decode(file)
+	img, err := decodeImage(file)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar imagem '%s': %w", path, err))
+		return nil
+	}
+
+	sketchImg := newSketchImage(img)
+	sketchImg.path = path
+
+	// Armazena no cache
+	loadedImages.put(path, sketchImg, info.ModTime())
+
+	return sketchImg
+}
+
+// LoadImageFS carrega uma imagem de um fs.FS, como um embed.FS
+// Útil para distribuir sketches como um único binário com os assets embutidos
+func LoadImageFS(fsys fs.FS, name string) *SketchImage {
+	defer trackAsset(name)
+
+	if fsys == nil {
+		reportError(newError(ErrNilArgument, "tentativa de carregar imagem '%s' de um sistema de arquivos nulo", name))
+		return nil
 	}
 
+	file, err := fsys.Open(name)
 	if err != nil {
-		reportError(fmt.Errorf("erro ao decodificar imagem '%s': %v", path, err))
+		reportError(newError(ErrLoadFailed, "erro ao abrir imagem '%s': %w", name, err))
 		return nil
 	}
+	defer file.Close()
 
-	// Converte para ebiten.Image
-	ebitenImg := ebiten.NewImageFromImage(img)
-	bounds := img.Bounds()
+	img, err := decodeImage(file)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar imagem '%s': %w", name, err))
+		return nil
+	}
 
-	sketchImg := &SketchImage{
-		img:    ebitenImg,
-		width:  bounds.Dx(),
-		height: bounds.Dy(),
+	return newSketchImage(img)
+}
+
+// LoadImageReader carrega uma imagem a partir de um io.Reader
+func LoadImageReader(r io.Reader) *SketchImage {
+	if r == nil {
+		reportError(newError(ErrNilArgument, "tentativa de carregar imagem de um reader nulo"))
+		return nil
 	}
 
-	// Armazena no cache
-	loadedImages[path] = sketchImg
+	img, err := decodeImage(r)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao decodificar imagem: %w", err))
+		return nil
+	}
 
-	return sketchImg
+	return newSketchImage(img)
+}
+
+// LoadImageBytes carrega uma imagem a partir de seu conteúdo codificado em memória
+func LoadImageBytes(data []byte) *SketchImage {
+	if len(data) == 0 {
+		reportError(newError(ErrNilArgument, "tentativa de carregar imagem de dados vazios"))
+		return nil
+	}
+
+	return LoadImageReader(bytes.NewReader(data))
+}
+
+// decodeImage decodifica uma imagem detectando o formato pelo conteúdo
+// Os formatos suportados são registrados pelos imports de decodificadores deste arquivo
+func decodeImage(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	return img, err
 }
 
-// Image desenha uma imagem no canvas
-func Image(img *SketchImage, x, y float64, dimensions ...float64) {
+// newSketchImage converte uma image.Image decodificada em SketchImage
+func newSketchImage(img image.Image) *SketchImage {
+	bounds := img.Bounds()
+	return &SketchImage{
+		img:    ebiten.NewImageFromImage(img),
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+	}
+}
+
+// Image desenha uma imagem ou o quadro atual de uma animação no canvas
+// A interpretação de x, y e das dimensões opcionais depende de ImageMode.
+// Com apenas uma dimensão, a altura é calculada mantendo a proporção.
+func Image(src ImageSource, x, y float64, dimensions ...float64) {
+	if notRecordable("Image") {
+		return
+	}
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de desenhar imagem sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de desenhar imagem sem canvas inicializado"))
 		return
 	}
 
+	var img *SketchImage
+	if src != nil {
+		img = src.sketchImage()
+	}
 	if img == nil {
-		reportError(fmt.Errorf("tentativa de desenhar imagem nula"))
+		reportError(newError(ErrNilArgument, "tentativa de desenhar imagem nula"))
+		return
+	}
+
+	// Posição e dimensões na unidade atual (veja Units)
+	x, y = toPixels(x), toPixels(y)
+	scaled := make([]float64, len(dimensions))
+	for i, d := range dimensions {
+		scaled[i] = toPixels(d)
+	}
+
+	x, y, w, h := imageRect(img, x, y, scaled)
+	if w == 0 || h == 0 {
 		return
 	}
 
 	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(w/float64(img.width), h/float64(img.height))
 	opts.GeoM.Translate(x, y)
+	opts.GeoM.Scale(canvas.density, canvas.density)
+	if tintEnabled {
+		opts.ColorScale.ScaleWithColor(tintColor)
+	}
 
-	// Se largura e altura foram especificadas, aplica escala
-	if len(dimensions) >= 2 {
-		scaleX := dimensions[0] / float64(img.width)
-		scaleY := dimensions[1] / float64(img.height)
-		opts.GeoM.Scale(scaleX, scaleY)
-	} else if len(dimensions) == 1 {
+	canvas.img.DrawImage(img.texture(), opts)
+}
+
+// imageRect converte os argumentos de Image no retângulo de destino (x, y, w, h)
+// de acordo com o modo de imagem atual
+func imageRect(img *SketchImage, x, y float64, dimensions []float64) (float64, float64, float64, float64) {
+	w, h := float64(img.width), float64(img.height)
+
+	switch {
+	case len(dimensions) >= 2 && imageMode == CORNERS:
+		x2, y2 := dimensions[0], dimensions[1]
+		w, h = math.Abs(x2-x), math.Abs(y2-y)
+		x, y = math.Min(x, x2), math.Min(y, y2)
+	case len(dimensions) >= 2:
+		w, h = dimensions[0], dimensions[1]
+	case len(dimensions) == 1:
 		// Apenas largura especificada, mantém proporção
-		scale := dimensions[0] / float64(img.width)
-		opts.GeoM.Scale(scale, scale)
+		w, h = dimensions[0], dimensions[0]*float64(img.height)/float64(img.width)
+	}
+
+	if imageMode == CENTER {
+		x -= w / 2
+		y -= h / 2
+	}
+	return x, y, w, h
+}
+
+// ImageMode define como os argumentos de Image são interpretados (CORNER, CORNERS ou CENTER)
+// Similar à função imageMode() do p5.js/Processing
+func ImageMode(mode int) {
+	switch mode {
+	case CORNER, CORNERS, CENTER:
+		imageMode = mode
+	default:
+		reportError(newError(ErrInvalidArgument, "modo de imagem inválido: %d - use CORNER, CORNERS ou CENTER", mode))
+	}
+}
+
+// Tint define uma cor que multiplica as imagens desenhadas por Image
+// O alfa da cor controla a transparência da imagem
+// Similar à função tint() do p5.js/Processing
+func Tint(c ColorValue) {
+	tintColor = ParseColorValue(c)
+	tintEnabled = true
+}
+
+// NoTint remove o tingimento aplicado por Tint
+func NoTint() { tintEnabled = false }
+
+// Get retorna uma cópia de uma região retangular do canvas como uma nova imagem
+// Pixels fora dos limites do canvas ficam transparentes
+// A região é dada em pixels físicos (veja PixelDensity).
+// Similar à função get(x, y, w, h) do p5.js/Processing
+func Get(x, y, w, h int) *SketchImage {
+	if canvas == nil {
+		reportError(newError(ErrNoCanvas, "tentativa de obter região sem canvas inicializado"))
+		return nil
+	}
+
+	if w <= 0 || h <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões de região inválidas: %dx%d", w, h))
+		return nil
+	}
+
+	return &SketchImage{
+		img:    copyRegion(canvas.img, x, y, w, h),
+		width:  w,
+		height: h,
+	}
+}
+
+// CopyRegion copia a região (sx, sy, sw, sh) de src para a região (dx, dy, dw, dh) do canvas,
+// escalando se os tamanhos forem diferentes. Se src for nil, a origem é o próprio canvas.
+// As regiões do canvas são dadas em pixels físicos (veja PixelDensity).
+// Similar à função copy() do p5.js/Processing
+func CopyRegion(src *SketchImage, sx, sy, sw, sh, dx, dy, dw, dh int) {
+	if notRecordable("CopyRegion") {
+		return
+	}
+	if canvas == nil {
+		reportError(newError(ErrNoCanvas, "tentativa de copiar região sem canvas inicializado"))
+		return
+	}
+
+	if sw <= 0 || sh <= 0 || dw <= 0 || dh <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões de cópia inválidas: origem %dx%d, destino %dx%d", sw, sh, dw, dh))
+		return
+	}
+
+	// A região é sempre copiada para uma imagem intermediária, pois o Ebiten
+	// não permite desenhar uma imagem sobre ela mesma
+	source := canvas.img
+	if src != nil {
+		source = src.texture()
+	}
+	region := copyRegion(source, sx, sy, sw, sh)
+	defer region.Deallocate()
+
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(float64(dw)/float64(sw), float64(dh)/float64(sh))
+	opts.GeoM.Translate(float64(dx), float64(dy))
+	canvas.img.DrawImage(region, opts)
+}
+
+// copyRegion copia a região (x, y, w, h) de src para uma nova imagem de tamanho w×h
+// Áreas fora dos limites de src ficam transparentes
+func copyRegion(src *ebiten.Image, x, y, w, h int) *ebiten.Image {
+	dst := ebiten.NewImage(w, h)
+
+	r := image.Rect(x, y, x+w, y+h).Intersect(src.Bounds())
+	if r.Empty() {
+		return dst
 	}
 
-	canvas.img.DrawImage(img.img, opts)
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(r.Min.X-x), float64(r.Min.Y-y))
+	dst.DrawImage(src.SubImage(r).(*ebiten.Image), opts)
+	return dst
 }
 
 // GetPixel retorna a cor de um pixel específico do canvas
+// As coordenadas são em pixels físicos: com PixelDensity(d), o canvas tem Width*d x Height*d pixels.
 func GetPixel(x, y int) color.Color {
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de obter pixel sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de obter pixel sem canvas inicializado"))
 		return color.Black
 	}
 
-	if x < 0 || x >= canvas.Width || y < 0 || y >= canvas.Height {
-		reportError(fmt.Errorf("coordenadas de pixel fora dos limites: (%d, %d)", x, y))
+	if x < 0 || x >= canvas.GetWidth() || y < 0 || y >= canvas.GetHeight() {
+		reportError(newError(ErrOutOfBounds, "coordenadas de pixel fora dos limites: (%d, %d)", x, y))
 		return color.Black
 	}
 
 	return canvas.img.At(x, y)
 }
 
-// SetPixel define a cor de um pixel específico do canvas
+// SetPixel define a cor de um pixel específico do canvas, em pixels físicos
 func SetPixel(x, y int, c ColorValue) {
+	if notRecordable("SetPixel") {
+		return
+	}
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de definir pixel sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de definir pixel sem canvas inicializado"))
 		return
 	}
 
-	if x < 0 || x >= canvas.Width || y < 0 || y >= canvas.Height {
-		reportError(fmt.Errorf("coordenadas de pixel fora dos limites: (%d, %d)", x, y))
+	if x < 0 || x >= canvas.GetWidth() || y < 0 || y >= canvas.GetHeight() {
+		reportError(newError(ErrOutOfBounds, "coordenadas de pixel fora dos limites: (%d, %d)", x, y))
 		return
 	}
 
@@ -149,17 +370,19 @@ func SetPixel(x, y int, c ColorValue) {
 }
 
 // LoadPixels carrega os pixels do canvas para o array pixels[]
+// O array tem a resolução física do canvas: Height*d linhas de Width*d pixels.
 func LoadPixels() {
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de carregar pixels sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de carregar pixels sem canvas inicializado"))
 		return
 	}
 
 	// Inicializa o array de pixels
-	pixels = make([][]color.Color, canvas.Height)
-	for y := 0; y < canvas.Height; y++ {
-		pixels[y] = make([]color.Color, canvas.Width)
-		for x := 0; x < canvas.Width; x++ {
+	width, height := canvas.GetWidth(), canvas.GetHeight()
+	pixels = make([][]color.Color, height)
+	for y := 0; y < height; y++ {
+		pixels[y] = make([]color.Color, width)
+		for x := 0; x < width; x++ {
 			pixels[y][x] = canvas.img.At(x, y)
 		}
 	}
@@ -167,18 +390,21 @@ func LoadPixels() {
 
 // UpdatePixels aplica as mudanças do array pixels[] de volta ao canvas
 func UpdatePixels() {
+	if notRecordable("UpdatePixels") {
+		return
+	}
 	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de atualizar pixels sem canvas inicializado"))
+		reportError(newError(ErrNoCanvas, "tentativa de atualizar pixels sem canvas inicializado"))
 		return
 	}
 
 	if pixels == nil {
-		reportError(fmt.Errorf("tentativa de atualizar pixels sem carregar pixels primeiro"))
+		reportError(newError(ErrInvalidState, "tentativa de atualizar pixels sem carregar pixels primeiro"))
 		return
 	}
 
-	for y := 0; y < len(pixels) && y < canvas.Height; y++ {
-		for x := 0; x < len(pixels[y]) && x < canvas.Width; x++ {
+	for y := 0; y < len(pixels) && y < canvas.GetHeight(); y++ {
+		for x := 0; x < len(pixels[y]) && x < canvas.GetWidth(); x++ {
 			canvas.img.Set(x, y, pixels[y][x])
 		}
 	}
@@ -189,60 +415,13 @@ func GetPixels() [][]color.Color {
 	return pixels
 }
 
-// Text desenha texto no canvas
-func Text(str string, x, y float64) {
-	if canvas == nil {
-		reportError(fmt.Errorf("tentativa de desenhar texto sem canvas inicializado"))
-		return
-	}
-
-	text.Draw(canvas.img, str, currentFont, int(x), int(y), textColor)
-}
-
-// TextSize define o tamanho do texto (funcionalidade limitada com basicfont)
-func TextSize(size float64) {
-	if size <= 0 {
-		reportError(fmt.Errorf("tamanho de texto inválido: %.2f - deve ser positivo", size))
-		return
-	}
-	textSize = size
-	// Nota: basicfont tem tamanho fixo, esta função é para compatibilidade
-}
-
-// TextColor define a cor do texto
-func TextColor(c ColorValue) {
-	textColor = ParseColorValue(c)
-}
-
-// TextWidth retorna a largura aproximada de um texto
-func TextWidth(str string) int {
-	if currentFont == nil {
-		return len(str) * 7 // Estimativa para basicfont
-	}
-
-	width := 0
-	for _, r := range str {
-		advance, ok := currentFont.GlyphAdvance(r)
-		if ok {
-			width += int(advance >> 6) // Converte de fixed.Int26_6 para int
-		} else {
-			width += 7 // Largura padrão
-		}
-	}
-	return width
-}
+// Métodos para SketchImage
 
-// TextHeight retorna a altura do texto atual
-func TextHeight() int {
-	if currentFont == nil {
-		return 13 // Altura padrão do basicfont
-	}
-	metrics := currentFont.Metrics()
-	return int(metrics.Height >> 6) // Converte de fixed.Int26_6 para int
+// sketchImage permite que SketchImage seja usada como ImageSource
+func (img *SketchImage) sketchImage() *SketchImage {
+	return img
 }
 
-// Métodos para SketchImage
-
 // Width retorna a largura da imagem
 func (img *SketchImage) Width() int {
 	return img.width
@@ -258,13 +437,13 @@ func (img *SketchImage) GetPixel(x, y int) color.Color {
 	if x < 0 || x >= img.width || y < 0 || y >= img.height {
 		return color.Black
 	}
-	return img.img.At(x, y)
+	return img.texture().At(x, y)
 }
 
 // Copy cria uma cópia da imagem
 func (img *SketchImage) Copy() *SketchImage {
 	newImg := ebiten.NewImage(img.width, img.height)
-	newImg.DrawImage(img.img, nil)
+	newImg.DrawImage(img.texture(), nil)
 
 	return &SketchImage{
 		img:    newImg,
@@ -273,10 +452,25 @@ func (img *SketchImage) Copy() *SketchImage {
 	}
 }
 
+// Crop retorna uma nova imagem com a região (x, y, w, h) desta imagem
+// Pixels fora dos limites da imagem ficam transparentes
+func (img *SketchImage) Crop(x, y, w, h int) *SketchImage {
+	if w <= 0 || h <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões de recorte inválidas: %dx%d", w, h))
+		return nil
+	}
+
+	return &SketchImage{
+		img:    copyRegion(img.texture(), x, y, w, h),
+		width:  w,
+		height: h,
+	}
+}
+
 // Resize redimensiona a imagem (cria uma nova imagem)
 func (img *SketchImage) Resize(newWidth, newHeight int) *SketchImage {
 	if newWidth <= 0 || newHeight <= 0 {
-		reportError(fmt.Errorf("dimensões de redimensionamento inválidas: %dx%d", newWidth, newHeight))
+		reportError(newError(ErrInvalidDimensions, "dimensões de redimensionamento inválidas: %dx%d", newWidth, newHeight))
 		return img
 	}
 
@@ -287,7 +481,7 @@ func (img *SketchImage) Resize(newWidth, newHeight int) *SketchImage {
 	scaleY := float64(newHeight) / float64(img.height)
 	opts.GeoM.Scale(scaleX, scaleY)
 
-	newImg.DrawImage(img.img, opts)
+	newImg.DrawImage(img.texture(), opts)
 
 	return &SketchImage{
 		img:    newImg,
@@ -299,7 +493,7 @@ func (img *SketchImage) Resize(newWidth, newHeight int) *SketchImage {
 // CreateImage cria uma nova imagem vazia
 func CreateImage(width, height int) *SketchImage {
 	if width <= 0 || height <= 0 {
-		reportError(fmt.Errorf("dimensões de imagem inválidas: %dx%d", width, height))
+		reportError(newError(ErrInvalidDimensions, "dimensões de imagem inválidas: %dx%d", width, height))
 		return nil
 	}
 
@@ -312,36 +506,116 @@ func CreateImage(width, height int) *SketchImage {
 }
 
 // SaveImage salva o canvas atual como imagem
+// A imagem tem a resolução física do canvas (Width*d x Height*d, veja PixelDensity).
+// Se o canvas tiver resolução em DPI (veja CreateCanvasPaper e DPI), ela é gravada
+// no bloco pHYs do PNG ou na densidade JFIF do JPEG. PNGs também recebem os
+// metadados de SaveMetadata, quando ativados.
 func SaveImage(filename string) error {
 	if canvas == nil {
-		return fmt.Errorf("tentativa de salvar imagem sem canvas inicializado")
+		return newError(ErrNoCanvas, "tentativa de salvar imagem sem canvas inicializado")
 	}
 
 	// Cria o arquivo
 	file, err := os.Create(filename)
 	if err != nil {
-		return fmt.Errorf("erro ao criar arquivo '%s': %v", filename, err)
+		return newError(ErrWriteFailed, "erro ao criar arquivo '%s': %w", filename, err)
 	}
 	defer file.Close()
 
 	// Converte ebiten.Image para image.Image
-	bounds := image.Rect(0, 0, canvas.Width, canvas.Height)
+	width, height := canvas.GetWidth(), canvas.GetHeight()
+	bounds := image.Rect(0, 0, width, height)
 	img := image.NewRGBA(bounds)
 
-	for y := 0; y < canvas.Height; y++ {
-		for x := 0; x < canvas.Width; x++ {
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
 			img.Set(x, y, canvas.img.At(x, y))
 		}
 	}
 
-	// Salva baseado na extensão
+	// Salva baseado na extensão (padrão PNG)
+	var buf bytes.Buffer
 	ext := strings.ToLower(filepath.Ext(filename))
-	switch ext {
-	case ".png":
-		return png.Encode(file, img)
-	case ".jpg", ".jpeg":
-		return jpeg.Encode(file, img, &jpeg.Options{Quality: 90})
-	default:
-		return png.Encode(file, img) // Padrão para PNG
+	isJPEG := ext == ".jpg" || ext == ".jpeg"
+	if isJPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, img)
 	}
+	if err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	if isJPEG {
+		if dpi, ok := imageDPI(); ok {
+			data = insertJFIFDensity(data, dpi)
+		}
+	} else {
+		var chunks []pngChunk
+		if dpi, ok := imageDPI(); ok {
+			chunks = append(chunks, pngChunk{typ: "pHYs", data: pngPhysicalDimensions(dpi)})
+		}
+		if meta := currentMetadata(); meta != nil {
+			chunks = append(chunks, meta.pngChunks()...)
+		}
+		data = insertPNGChunks(data, chunks)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		return newError(ErrWriteFailed, "erro ao escrever arquivo '%s': %w", filename, err)
+	}
+	return nil
+}
+
+// insertPNGChunks insere blocos em um PNG codificado, em ordem, logo após o IHDR
+// Blocos como pHYs e tEXt precisam vir antes dos dados da imagem (IDAT).
+func insertPNGChunks(data []byte, chunks []pngChunk) []byte {
+	if len(chunks) == 0 {
+		return data
+	}
+	const ihdrEnd = len(pngSignature) + 8 + 13 + 4 // Assinatura + IHDR (tamanho, tipo, dados, CRC)
+
+	var buf bytes.Buffer
+	buf.Write(data[:ihdrEnd])
+	for _, chunk := range chunks {
+		writePNGChunk(&buf, chunk.typ, chunk.data)
+	}
+	buf.Write(data[ihdrEnd:])
+	return buf.Bytes()
+}
+
+// pngPhysicalDimensions retorna os dados do bloco pHYs para a resolução em DPI
+func pngPhysicalDimensions(dpi float64) []byte {
+	ppm := uint32(math.Round(dpi / 0.0254)) // Pixels por metro
+
+	data := make([]byte, 9)
+	binary.BigEndian.PutUint32(data[0:4], ppm)
+	binary.BigEndian.PutUint32(data[4:8], ppm)
+	data[8] = 1 // Unidade: metro
+	return data
+}
+
+// insertJFIFDensity insere, logo após o marcador SOI de um JPEG codificado, o segmento
+// APP0 JFIF com a resolução em DPI (o codificador da biblioteca padrão não o escreve)
+func insertJFIFDensity(data []byte, dpi float64) []byte {
+	density := uint16(math.Min(math.Round(dpi), math.MaxUint16))
+
+	app0 := []byte{
+		0xFF, 0xE0, // Marcador APP0
+		0, 16, // Tamanho do segmento
+		'J', 'F', 'I', 'F', 0,
+		1, 2, // Versão 1.02
+		1,          // Unidade: pontos por polegada
+		0, 0, 0, 0, // Densidade horizontal e vertical
+		0, 0, // Sem miniatura
+	}
+	binary.BigEndian.PutUint16(app0[12:14], density)
+	binary.BigEndian.PutUint16(app0[14:16], density)
+
+	var buf bytes.Buffer
+	buf.Write(data[:2]) // SOI
+	buf.Write(app0)
+	buf.Write(data[2:])
+	return buf.Bytes()
 }
@@ -0,0 +1,271 @@
+/*
+Projeto: GoSketch - Cache de Imagens
+Descrição: Cache das imagens carregadas por LoadImage, com limite de memória,
+remoção LRU (menos usada recentemente), descarte explícito e invalidação
+opcional quando o arquivo é modificado no disco. O cache é protegido por um mutex,
+permitindo carregar imagens em goroutines. Imagens removidas do cache têm a memória da
+GPU liberada na hora e são recarregadas do disco se o sketch voltar a desenhá-las
+*/
+
+package gosketch
+
+import (
+	"container/list"
+	"image"
+	"image/draw"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// imageCacheEntry representa uma imagem armazenada no cache
+type imageCacheEntry struct {
+	path    string
+	img     *SketchImage
+	size    int64     // Memória estimada em bytes (RGBA, 4 bytes por pixel)
+	modTime time.Time // Data de modificação do arquivo quando foi carregado
+}
+
+// imageCache armazena imagens por caminho mantendo a ordem de uso
+type imageCache struct {
+	mu           sync.Mutex
+	entries      map[string]*list.Element
+	order        *list.List // Frente = usada mais recentemente
+	used         int64      // Memória total estimada das imagens no cache
+	limit        int64      // Limite de memória em bytes (0 = sem limite)
+	checkModTime bool       // Recarrega a imagem se o arquivo mudou no disco
+}
+
+// newImageCache cria um cache vazio e sem limite de memória
+func newImageCache() *imageCache {
+	return &imageCache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get retorna a imagem em cache para path, marcando-a como usada recentemente
+// Se a verificação de modificação estiver ativa e o arquivo tiver mudado, a entrada
+// é descartada e get retorna nil para forçar o recarregamento
+func (c *imageCache) get(path string) *SketchImage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.entries[path]
+	if !exists {
+		return nil
+	}
+
+	entry := elem.Value.(*imageCacheEntry)
+	if c.checkModTime {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(entry.modTime) {
+			c.removeEntry(path)
+			entry.img.release()
+			return nil
+		}
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.img
+}
+
+// put armazena img no cache e remove as imagens menos usadas se o limite for excedido
+func (c *imageCache) put(path string, img *SketchImage, modTime time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(path, img, modTime)
+}
+
+// restore devolve ao cache uma imagem recarregada, a menos que path já contenha outra
+// imagem (carregada de novo pelo sketch depois que img foi liberada)
+// Retorna false quando img não voltou ao cache.
+func (c *imageCache) restore(path string, img *SketchImage, modTime time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, exists := c.entries[path]; exists && elem.Value.(*imageCacheEntry).img != img {
+		return false
+	}
+	c.insert(path, img, modTime)
+	return true
+}
+
+// insert substitui a entrada de path por img e aplica o limite de memória;
+// o chamador deve manter c.mu
+func (c *imageCache) insert(path string, img *SketchImage, modTime time.Time) {
+	c.removeEntry(path)
+
+	entry := &imageCacheEntry{
+		path:    path,
+		img:     img,
+		size:    int64(img.width) * int64(img.height) * 4,
+		modTime: modTime,
+	}
+	c.entries[path] = c.order.PushFront(entry)
+	c.used += entry.size
+
+	c.evict()
+}
+
+// remove descarta a entrada de path, se existir, liberando a memória da imagem
+func (c *imageCache) remove(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.removeEntry(path)
+	if entry == nil {
+		return false
+	}
+	entry.img.release()
+	return true
+}
+
+// removeEntry retira a entrada de path do cache sem liberar a imagem, retornando-a
+// (nil se não existir); o chamador deve manter c.mu
+func (c *imageCache) removeEntry(path string) *imageCacheEntry {
+	elem, exists := c.entries[path]
+	if !exists {
+		return nil
+	}
+
+	entry := c.order.Remove(elem).(*imageCacheEntry)
+	delete(c.entries, path)
+	c.used -= entry.size
+	return entry
+}
+
+// evict remove as imagens menos usadas até respeitar o limite de memória
+// A imagem usada mais recentemente nunca é removida, mesmo que sozinha exceda o limite.
+// O chamador deve manter c.mu.
+func (c *imageCache) evict() {
+	if c.limit <= 0 {
+		return
+	}
+	for c.used > c.limit && c.order.Len() > 1 {
+		oldest := c.order.Back().Value.(*imageCacheEntry)
+		c.removeEntry(oldest.path)
+		oldest.img.release()
+	}
+}
+
+// clear descarta todas as entradas do cache, liberando a memória das imagens
+func (c *imageCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*imageCacheEntry).img.release()
+	}
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.used = 0
+}
+
+// release libera a memória da GPU usada pela imagem removida do cache
+// A imagem continua válida: se o sketch ainda a usar, texture a recarrega do disco.
+func (img *SketchImage) release() {
+	if img.released.CompareAndSwap(false, true) {
+		img.img.Deallocate()
+	}
+}
+
+// texture retorna a imagem do Ebiten, recarregando-a do disco e devolvendo-a ao cache
+// se ela foi liberada
+// Se o sketch já carregou o mesmo caminho de novo, a imagem nova continua no cache e
+// esta é recarregada fora dele, sem contar para SetImageCacheLimit.
+func (img *SketchImage) texture() *ebiten.Image {
+	if !img.released.Load() {
+		return img.img
+	}
+
+	// Goroutines que desenham a mesma imagem esperam a primeira terminar de recarregá-la
+	img.reloadMu.Lock()
+	defer img.reloadMu.Unlock()
+	if !img.released.Load() {
+		return img.img
+	}
+
+	if err := img.reload(); err != nil {
+		reportError(err)
+	}
+	// Mesmo em caso de erro a imagem não é recarregada de novo a cada quadro. A flag é
+	// limpa antes de voltar ao cache para que uma remoção logo depois a libere de novo.
+	img.released.Store(false)
+	loadedImages.restore(img.path, img, img.modTime())
+	return img.img
+}
+
+// reload escreve o conteúdo atual do arquivo da imagem na imagem liberada
+// Pixels além das dimensões originais são ignorados, se o arquivo tiver mudado de tamanho.
+func (img *SketchImage) reload() error {
+	file, err := os.Open(img.path)
+	if err != nil {
+		return newError(ErrLoadFailed, "erro ao recarregar imagem '%s': %w", img.path, err)
+	}
+	defer file.Close()
+
+	decoded, err := decodeImage(file)
+	if err != nil {
+		return newError(ErrLoadFailed, "erro ao decodificar imagem '%s': %w", img.path, err)
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, img.width, img.height))
+	draw.Draw(rgba, rgba.Rect, decoded, decoded.Bounds().Min, draw.Src)
+	img.img.WritePixels(rgba.Pix)
+	return nil
+}
+
+// modTime retorna a data de modificação atual do arquivo da imagem
+func (img *SketchImage) modTime() time.Time {
+	info, err := os.Stat(img.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// UnloadImage remove a imagem carregada de path do cache e libera sua memória na GPU
+// Referências mantidas pelo sketch continuam válidas: se a imagem for desenhada de novo,
+// ela é recarregada do disco e volta ao cache.
+// Retorna false se a imagem não estava no cache.
+func UnloadImage(path string) bool {
+	return loadedImages.remove(path)
+}
+
+// ClearImageCache remove todas as imagens do cache e libera sua memória na GPU
+func ClearImageCache() {
+	loadedImages.clear()
+}
+
+// SetImageCacheLimit define o limite de memória do cache de imagens em bytes
+// Quando excedido, as imagens usadas há mais tempo são removidas do cache e têm a
+// memória liberada; são recarregadas do disco se forem desenhadas novamente.
+// Use 0 para desativar o limite (padrão).
+func SetImageCacheLimit(bytes int64) {
+	if bytes < 0 {
+		reportError(newError(ErrInvalidArgument, "limite de cache de imagens inválido: %d - deve ser não-negativo", bytes))
+		bytes = 0
+	}
+	loadedImages.mu.Lock()
+	defer loadedImages.mu.Unlock()
+	loadedImages.limit = bytes
+	loadedImages.evict()
+}
+
+// SetImageCacheModTimeCheck ativa ou desativa a verificação da data de modificação
+// Quando ativa, LoadImage recarrega a imagem se o arquivo mudou desde o carregamento.
+func SetImageCacheModTimeCheck(enabled bool) {
+	loadedImages.mu.Lock()
+	defer loadedImages.mu.Unlock()
+	loadedImages.checkModTime = enabled
+}
+
+// ImageCacheUsage retorna o número de imagens no cache e a memória estimada em bytes
+func ImageCacheUsage() (count int, bytes int64) {
+	loadedImages.mu.Lock()
+	defer loadedImages.mu.Unlock()
+	return loadedImages.order.Len(), loadedImages.used
+}
@@ -0,0 +1,56 @@
+package gosketch
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestReloadKeepsNewerCacheEntry(t *testing.T) {
+	captureErrors(t, func(err error) { t.Error(err) })
+	t.Cleanup(ClearImageCache)
+	path, _ := writeTestPNG(t, 4, 4)
+
+	old := LoadImage(path)
+	UnloadImage(path)
+	newer := LoadImage(path)
+	if newer == old {
+		t.Fatal("LoadImage retornou a imagem descarregada")
+	}
+
+	// Desenhar a referência antiga a recarrega sem tirar a nova do cache
+	old.texture()
+	if got := loadedImages.get(path); got != newer {
+		t.Errorf("cache contém %p; esperado a imagem carregada depois (%p)", got, newer)
+	}
+	if count, used := ImageCacheUsage(); count != 1 || used != 4*4*4 {
+		t.Errorf("cache com %d imagens e %d bytes; esperado apenas a imagem nova", count, used)
+	}
+}
+
+func TestConcurrentReload(t *testing.T) {
+	captureErrors(t, func(err error) { t.Error(err) })
+	t.Cleanup(ClearImageCache)
+	path, _ := writeTestPNG(t, 4, 4)
+
+	img := LoadImage(path)
+	UnloadImage(path)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if img.texture() == nil {
+				t.Error("texture retornou nil")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if img.released.Load() || loadedImages.get(path) != img {
+		t.Error("a imagem recarregada não voltou ao cache")
+	}
+	if count, _ := ImageCacheUsage(); count != 1 {
+		t.Errorf("cache com %d imagens; esperado 1", count)
+	}
+}
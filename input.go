@@ -0,0 +1,321 @@
+/*
+Projeto: GoSketch - Fluxo de Entrada
+Descrição: Estado bruto de entrada por quadro (mouse, teclas, roda e tamanho da janela),
+com gravação e reprodução determinística e uma API de simulação para testes
+*/
+
+package gosketch
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// inputFrame é o estado bruto de entrada em um quadro
+// Mouse e teclado derivam seus eventos comparando o quadro atual com o anterior.
+type inputFrame struct {
+	MouseX  float64       `json:"mx"`
+	MouseY  float64       `json:"my"`
+	Buttons []int         `json:"buttons,omitempty"` // Botões pressionados (LEFT, RIGHT, CENTER)
+	WheelX  float64       `json:"wx,omitempty"`
+	WheelY  float64       `json:"wy,omitempty"`
+	Keys    []KeyboardKey `json:"keys,omitempty"`  // Teclas pressionadas, gravadas pelo nome
+	Chars   string        `json:"chars,omitempty"` // Caracteres digitados neste quadro
+	Width   int           `json:"w"`               // Tamanho da janela
+	Height  int           `json:"h"`
+}
+
+// inputLog é o formato do arquivo de gravação de entrada
+type inputLog struct {
+	Version int          `json:"version"`
+	Frames  []inputFrame `json:"frames"`
+}
+
+// inputLogVersion é a versão atual do formato de gravação
+const inputLogVersion = 1
+
+// Estado global do fluxo de entrada
+var (
+	currentInput  inputFrame
+	previousInput inputFrame
+
+	// Gravação
+	inputRecordPath string
+	inputRecording  []inputFrame
+	isInputRecord   bool
+
+	// Reprodução
+	inputReplay    []inputFrame
+	inputReplayPos int
+
+	// Simulação
+	simInput       inputFrame
+	simActive      bool
+	simKeyReleases []KeyboardKey // Teclas soltas após o próximo quadro (SimulateKey)
+)
+
+// processInput avança um quadro de entrada: obtém o estado da fonte atual
+// (reprodução, simulação ou dispositivos reais), grava se necessário e dispara os eventos
+// Chamada uma vez por quadro por internalGame.Update
+func processInput() {
+	previousInput = currentInput
+
+	switch {
+	case inputReplayPos < len(inputReplay):
+		currentInput = inputReplay[inputReplayPos]
+		inputReplayPos++
+		applyReplayedWindowSize(currentInput)
+	case simActive:
+		currentInput = nextSimulatedFrame()
+	default:
+		currentInput = pollInput()
+	}
+
+	if isInputRecord {
+		inputRecording = append(inputRecording, cloneInputFrame(currentInput))
+	}
+
+	updateMouse(&currentInput, &previousInput)
+	updateKeyboard(&currentInput, &previousInput)
+}
+
+// pollInput lê o estado dos dispositivos reais pelo Ebiten
+func pollInput() inputFrame {
+	var frame inputFrame
+
+	x, y := cursorPosition()
+	frame.MouseX, frame.MouseY = x, y
+	for _, b := range mouseButtons {
+		if ebiten.IsMouseButtonPressed(b.button) {
+			frame.Buttons = append(frame.Buttons, b.value)
+		}
+	}
+	frame.WheelX, frame.WheelY = ebiten.Wheel()
+
+	for _, k := range pollPressedKeys() {
+		frame.Keys = append(frame.Keys, KeyboardKey(k))
+	}
+	frame.Chars = string(ebiten.AppendInputChars(nil))
+
+	frame.Width, frame.Height = ebiten.WindowSize()
+	return frame
+}
+
+// applyReplayedWindowSize restaura o tamanho de janela gravado durante a reprodução
+func applyReplayedWindowSize(frame inputFrame) {
+	if frame.Width <= 0 || frame.Height <= 0 {
+		return
+	}
+	if w, h := ebiten.WindowSize(); w != frame.Width || h != frame.Height {
+		ebiten.SetWindowSize(frame.Width, frame.Height)
+	}
+}
+
+// cloneInputFrame copia um quadro sem compartilhar os slices
+func cloneInputFrame(frame inputFrame) inputFrame {
+	frame.Buttons = append([]int(nil), frame.Buttons...)
+	frame.Keys = append([]KeyboardKey(nil), frame.Keys...)
+	return frame
+}
+
+// ======= GRAVAÇÃO E REPRODUÇÃO =======
+
+// StartInputRecording começa a gravar o fluxo de entrada de cada quadro
+// O arquivo é escrito por StopInputRecording: JSON se a extensão for ".json",
+// caso contrário um formato binário compacto (gob).
+func StartInputRecording(path string) error {
+	if isInputRecord {
+		return newError(ErrRecording, "já existe uma gravação de entrada em andamento para '%s'", inputRecordPath)
+	}
+	inputRecordPath = path
+	inputRecording = nil
+	isInputRecord = true
+	return nil
+}
+
+// StopInputRecording encerra a gravação de entrada e escreve o arquivo
+func StopInputRecording() error {
+	if !isInputRecord {
+		return newError(ErrRecording, "nenhuma gravação de entrada em andamento")
+	}
+	isInputRecord = false
+
+	file, err := os.Create(inputRecordPath)
+	if err != nil {
+		return newError(ErrWriteFailed, "erro ao criar arquivo '%s': %w", inputRecordPath, err)
+	}
+	defer file.Close()
+
+	log := inputLog{Version: inputLogVersion, Frames: inputRecording}
+	inputRecording = nil
+
+	if isJSONPath(inputRecordPath) {
+		encoder := json.NewEncoder(file)
+		err = encoder.Encode(log)
+	} else {
+		err = gob.NewEncoder(file).Encode(log)
+	}
+	if err != nil {
+		return newError(ErrWriteFailed, "erro ao escrever gravação de entrada '%s': %w", inputRecordPath, err)
+	}
+	return nil
+}
+
+// ReplayInput carrega uma gravação de entrada e a reproduz a partir do próximo quadro,
+// um quadro gravado por quadro executado, ignorando os dispositivos reais.
+// Ao final da gravação, a entrada volta a vir dos dispositivos.
+func ReplayInput(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return newError(ErrLoadFailed, "erro ao abrir gravação de entrada '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	var log inputLog
+	if isJSONPath(path) {
+		err = json.NewDecoder(file).Decode(&log)
+	} else {
+		err = gob.NewDecoder(file).Decode(&log)
+	}
+	if err != nil {
+		return newError(ErrLoadFailed, "erro ao ler gravação de entrada '%s': %w", path, err)
+	}
+
+	if log.Version != inputLogVersion {
+		return newError(ErrUnsupported, "versão de gravação de entrada não suportada em '%s': %d", path, log.Version)
+	}
+
+	inputReplay = log.Frames
+	inputReplayPos = 0
+	return nil
+}
+
+// IsReplayingInput retorna se uma gravação de entrada está sendo reproduzida
+func IsReplayingInput() bool {
+	return inputReplayPos < len(inputReplay)
+}
+
+// isJSONPath retorna se o arquivo de gravação usa o formato JSON
+func isJSONPath(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".json"
+}
+
+// ======= SIMULAÇÃO =======
+
+// SimulateMouseMove move o mouse simulado para (x, y) no canvas
+// Qualquer chamada Simulate* desativa a leitura dos dispositivos reais até StopSimulation.
+func SimulateMouseMove(x, y float64) {
+	simActive = true
+	simInput.MouseX, simInput.MouseY = x, y
+}
+
+// SimulateMousePress pressiona um botão do mouse simulado (LEFT, RIGHT ou CENTER)
+func SimulateMousePress(button int) {
+	simActive = true
+	for _, b := range simInput.Buttons {
+		if b == button {
+			return
+		}
+	}
+	simInput.Buttons = append(simInput.Buttons, button)
+}
+
+// SimulateMouseRelease solta um botão do mouse simulado
+func SimulateMouseRelease(button int) {
+	simActive = true
+	for i, b := range simInput.Buttons {
+		if b == button {
+			simInput.Buttons = append(simInput.Buttons[:i], simInput.Buttons[i+1:]...)
+			return
+		}
+	}
+}
+
+// SimulateMouseWheel gira a roda do mouse simulado no próximo quadro
+func SimulateMouseWheel(dx, dy float64) {
+	simActive = true
+	simInput.WheelX += dx
+	simInput.WheelY += dy
+}
+
+// SimulateKeyDown pressiona uma tecla simulada até SimulateKeyUp
+func SimulateKeyDown(k KeyboardKey) {
+	simActive = true
+	for _, pressed := range simInput.Keys {
+		if pressed == k {
+			return
+		}
+	}
+	simInput.Keys = append(simInput.Keys, k)
+}
+
+// SimulateKeyUp solta uma tecla simulada
+func SimulateKeyUp(k KeyboardKey) {
+	simActive = true
+	for i, pressed := range simInput.Keys {
+		if pressed == k {
+			simInput.Keys = append(simInput.Keys[:i], simInput.Keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// SimulateKey pressiona e solta uma tecla: ela fica pressionada no próximo quadro
+// e é solta no seguinte. Letras, dígitos e espaço também geram o caractere digitado
+// (letras em minúsculas), disparando KeyTyped.
+func SimulateKey(k KeyboardKey) {
+	SimulateKeyDown(k)
+	simKeyReleases = append(simKeyReleases, k)
+
+	switch {
+	case k >= KeyA && k <= KeyZ:
+		simInput.Chars += string(rune('a' + int(k-KeyA)))
+	case k >= KeyDigit0 && k <= KeyDigit9:
+		simInput.Chars += string(rune('0' + int(k-KeyDigit0)))
+	case k == KeySpace:
+		simInput.Chars += " "
+	}
+}
+
+// SimulateTyping digita o texto no próximo quadro, disparando KeyTyped para cada caractere
+func SimulateTyping(text string) {
+	simActive = true
+	simInput.Chars += text
+}
+
+// StopSimulation volta a ler a entrada dos dispositivos reais
+func StopSimulation() {
+	simActive = false
+	simInput = inputFrame{}
+	simKeyReleases = nil
+}
+
+// StepInput processa um quadro de entrada imediatamente, disparando os callbacks
+// Permite testar a lógica interativa em testes Go sem abrir a janela: combine com
+// as funções Simulate* e verifique o estado do sketch após cada passo.
+func StepInput() {
+	processInput()
+}
+
+// nextSimulatedFrame retorna o estado simulado para este quadro e prepara o próximo
+// Roda e caracteres valem por um único quadro; teclas de SimulateKey são soltas em seguida.
+func nextSimulatedFrame() inputFrame {
+	frame := cloneInputFrame(simInput)
+	if frame.Width == 0 && canvas != nil {
+		frame.Width, frame.Height = canvas.Width, canvas.Height
+	}
+
+	simInput.WheelX, simInput.WheelY = 0, 0
+	simInput.Chars = ""
+	for _, k := range simKeyReleases {
+		SimulateKeyUp(k)
+	}
+	simKeyReleases = nil
+
+	return frame
+}
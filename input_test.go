@@ -0,0 +1,221 @@
+package gosketch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// resetInput limpa o estado de entrada e os callbacks antes e depois do teste
+func resetInput(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		StopSimulation()
+		currentInput, previousInput = inputFrame{}, inputFrame{}
+		inputReplay, inputReplayPos = nil, 0
+		isInputRecord, inputRecording = false, nil
+		mouseInitialized = false
+		keyPressedFn, keyReleasedFn, keyTypedFn = nil, nil, nil
+		mousePressedFn, mouseReleasedFn, mouseClickedFn = nil, nil, nil
+		mouseMovedFn, mouseDraggedFn, mouseWheelFn = nil, nil, nil
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestSimulateKey(t *testing.T) {
+	resetInput(t)
+
+	var pressed, typed []string
+	released := 0
+	KeyPressed(func() { pressed = append(pressed, Key()) })
+	KeyTyped(func() { typed = append(typed, Key()) })
+	KeyReleased(func() { released++ })
+
+	SimulateKey(KeyA)
+	StepInput()
+	if !KeyIsPressed() || KeyCode() != KeyA {
+		t.Fatalf("após SimulateKey: KeyIsPressed = %v, KeyCode = %v", KeyIsPressed(), KeyCode())
+	}
+	if !slices.Equal(pressed, []string{"a"}) || !slices.Equal(typed, []string{"a"}) {
+		t.Fatalf("keyPressed = %q, keyTyped = %q; esperado [\"a\"]", pressed, typed)
+	}
+
+	StepInput()
+	if KeyIsPressed() || released != 1 {
+		t.Fatalf("no quadro seguinte: KeyIsPressed = %v, keyReleased chamado %d vezes", KeyIsPressed(), released)
+	}
+}
+
+func TestSimulateKeysInSameFrame(t *testing.T) {
+	resetInput(t)
+
+	var pressed, typed []string
+	KeyPressed(func() { pressed = append(pressed, Key()) })
+	KeyTyped(func() { typed = append(typed, Key()) })
+
+	// Com duas teclas de texto no mesmo quadro, nenhuma recebe o caractere da outra
+	SimulateKeyDown(KeyA)
+	SimulateKeyDown(KeyB)
+	SimulateTyping("ba")
+	StepInput()
+
+	if !slices.Equal(pressed, []string{"A", "B"}) {
+		t.Errorf("keyPressed = %q; esperado os nomes das teclas", pressed)
+	}
+	if !slices.Equal(typed, []string{"b", "a"}) {
+		t.Errorf("keyTyped = %q; esperado os caracteres na ordem digitada", typed)
+	}
+}
+
+func TestSimulateMouse(t *testing.T) {
+	resetInput(t)
+
+	var events []string
+	MousePressed(func() { events = append(events, "pressed") })
+	MouseDragged(func() { events = append(events, "dragged") })
+	MouseReleased(func() { events = append(events, "released") })
+	MouseClicked(func() { events = append(events, "clicked") })
+	MouseMoved(func() { events = append(events, "moved") })
+	MouseWheel(func(dx, dy float64) { events = append(events, "wheel") })
+
+	SimulateMouseMove(10, 10)
+	StepInput()
+
+	SimulateMousePress(LEFT)
+	SimulateMouseMove(20, 30)
+	StepInput()
+	if MouseX() != 20 || MouseY() != 30 || PMouseX() != 10 || PMouseY() != 10 {
+		t.Fatalf("mouse = (%v, %v), anterior = (%v, %v)", MouseX(), MouseY(), PMouseX(), PMouseY())
+	}
+	if !MouseIsPressed() || MouseButton() != LEFT {
+		t.Fatalf("MouseIsPressed = %v, MouseButton = %v", MouseIsPressed(), MouseButton())
+	}
+
+	SimulateMouseRelease(LEFT)
+	SimulateMouseWheel(0, 3)
+	StepInput()
+	if dx, dy := MouseWheelDelta(); dx != 0 || dy != 3 {
+		t.Fatalf("MouseWheelDelta = (%v, %v); esperado (0, 3)", dx, dy)
+	}
+
+	// A roda vale por um único quadro
+	StepInput()
+	if _, dy := MouseWheelDelta(); dy != 0 {
+		t.Fatalf("roda continua ativa no quadro seguinte: %v", dy)
+	}
+
+	want := []string{"pressed", "dragged", "released", "clicked", "wheel"}
+	if !slices.Equal(events, want) {
+		t.Errorf("eventos = %q; esperado %q", events, want)
+	}
+}
+
+func TestInputRecordAndReplay(t *testing.T) {
+	resetInput(t)
+	path := filepath.Join(t.TempDir(), "input.json")
+
+	if err := StartInputRecording(path); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		SimulateMouseMove(float64(i*10), float64(i))
+		if i == 2 {
+			SimulateKey(KeySpace)
+		}
+		StepInput()
+	}
+	if err := StopInputRecording(); err != nil {
+		t.Fatal(err)
+	}
+	StopSimulation()
+
+	var xs []float64
+	typed := ""
+	MouseMoved(func() { xs = append(xs, MouseX()) })
+	KeyTyped(func() { typed += Key() })
+
+	if err := ReplayInput(path); err != nil {
+		t.Fatal(err)
+	}
+	mouseInitialized = false
+	for IsReplayingInput() {
+		StepInput()
+	}
+
+	if want := []float64{10, 20, 30, 40}; !slices.Equal(xs, want) {
+		t.Errorf("posições reproduzidas = %v; esperado %v", xs, want)
+	}
+	if typed != " " {
+		t.Errorf("caracteres reproduzidos = %q; esperado \" \"", typed)
+	}
+}
+
+func TestKeyIsDownSeesSimulatedAndReplayedKeys(t *testing.T) {
+	resetInput(t)
+	path := filepath.Join(t.TempDir(), "arrows.gob")
+
+	if err := StartInputRecording(path); err != nil {
+		t.Fatal(err)
+	}
+	SimulateKeyDown(KeyArrowLeft)
+	SimulateKeyDown(KeyArrowUp)
+	StepInput()
+	if !KeyIsDown(KeyArrowLeft) || !KeyIsDown(KeyArrowUp) || KeyIsDown(KeyArrowRight) {
+		t.Fatalf("KeyIsDown com teclas simuladas: esquerda = %v, cima = %v, direita = %v",
+			KeyIsDown(KeyArrowLeft), KeyIsDown(KeyArrowUp), KeyIsDown(KeyArrowRight))
+	}
+	SimulateKeyUp(KeyArrowUp)
+	StepInput()
+	if err := StopInputRecording(); err != nil {
+		t.Fatal(err)
+	}
+	StopSimulation()
+
+	// Movimentação com setas reproduzida quadro a quadro
+	if err := ReplayInput(path); err != nil {
+		t.Fatal(err)
+	}
+	var frames [][2]bool
+	for IsReplayingInput() {
+		StepInput()
+		frames = append(frames, [2]bool{KeyIsDown(KeyArrowLeft), KeyIsDown(KeyArrowUp)})
+	}
+	if want := [][2]bool{{true, true}, {true, false}}; !slices.Equal(frames, want) {
+		t.Errorf("KeyIsDown reproduzido = %v; esperado %v", frames, want)
+	}
+}
+
+func TestInputRecordingStoresKeyNames(t *testing.T) {
+	resetInput(t)
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	if err := StartInputRecording(path); err != nil {
+		t.Fatal(err)
+	}
+	SimulateKeyDown(KeyArrowLeft)
+	SimulateKeyDown(KeyShiftLeft)
+	StepInput()
+	if err := StopInputRecording(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"keys":["ArrowLeft","ShiftLeft"]`) {
+		t.Errorf("gravação não contém os nomes das teclas: %s", data)
+	}
+
+	// Um nome desconhecido é rejeitado em vez de virar outra tecla
+	invalid := strings.Replace(string(data), "ArrowLeft", "TeclaInexistente", 1)
+	if err := os.WriteFile(path, []byte(invalid), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplayInput(path); err == nil {
+		t.Error("ReplayInput aceitou uma tecla desconhecida")
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Projeto: GoSketch - Locale
+Descrição: Normalização de códigos de idioma e detecção do idioma pelo ambiente,
+compartilhadas pela biblioteca e pela CLI
+*/
+
+package locale
+
+import (
+	"os"
+	"strings"
+)
+
+// Idiomas das mensagens
+const (
+	PTBR = "pt-BR"
+	EN   = "en"
+)
+
+// Normalize converte um código de idioma ou locale para PTBR ou EN
+// Variantes como "pt", "pt_BR.UTF-8" ou "en-US" também são aceitas.
+func Normalize(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	// Remove a codificação e o modificador do locale (pt_BR.UTF-8@euro)
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+
+	switch {
+	case lang == "pt" || strings.HasPrefix(lang, "pt-") || strings.HasPrefix(lang, "pt_"):
+		return PTBR, true
+	case lang == "en" || strings.HasPrefix(lang, "en-") || strings.HasPrefix(lang, "en_"):
+		return EN, true
+	}
+	return "", false
+}
+
+// Detect escolhe o idioma a partir do ambiente, na ordem de precedência do POSIX
+// (LC_ALL, LC_MESSAGES e LANG). Sem locale definido (ou com C/POSIX), o idioma é
+// pt-BR; outros idiomas não suportados usam inglês.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if lang, ok := Normalize(value); ok {
+			return lang
+		}
+		if value == "C" || value == "POSIX" || strings.HasPrefix(value, "C.") {
+			return PTBR
+		}
+		return EN
+	}
+	return PTBR
+}
@@ -0,0 +1,292 @@
+/*
+Projeto: GoSketch - Teclado
+Descrição: Estado do teclado, constantes de teclas e callbacks de eventos inspirados
+em p5.js, com entrada de texto Unicode a partir dos caracteres digitados,
+derivados a cada quadro do fluxo de entrada (input.go)
+*/
+
+package gosketch
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// KeyboardKey identifica uma tecla física do teclado
+// Use as constantes KeyA, KeySpace, KeyArrowLeft, etc. com KeyIsDown e KeyCode.
+type KeyboardKey int
+
+// Constantes de teclas, para que sketches não precisem importar o Ebiten
+const (
+	// Letras
+	KeyA = KeyboardKey(ebiten.KeyA)
+	KeyB = KeyboardKey(ebiten.KeyB)
+	KeyC = KeyboardKey(ebiten.KeyC)
+	KeyD = KeyboardKey(ebiten.KeyD)
+	KeyE = KeyboardKey(ebiten.KeyE)
+	KeyF = KeyboardKey(ebiten.KeyF)
+	KeyG = KeyboardKey(ebiten.KeyG)
+	KeyH = KeyboardKey(ebiten.KeyH)
+	KeyI = KeyboardKey(ebiten.KeyI)
+	KeyJ = KeyboardKey(ebiten.KeyJ)
+	KeyK = KeyboardKey(ebiten.KeyK)
+	KeyL = KeyboardKey(ebiten.KeyL)
+	KeyM = KeyboardKey(ebiten.KeyM)
+	KeyN = KeyboardKey(ebiten.KeyN)
+	KeyO = KeyboardKey(ebiten.KeyO)
+	KeyP = KeyboardKey(ebiten.KeyP)
+	KeyQ = KeyboardKey(ebiten.KeyQ)
+	KeyR = KeyboardKey(ebiten.KeyR)
+	KeyS = KeyboardKey(ebiten.KeyS)
+	KeyT = KeyboardKey(ebiten.KeyT)
+	KeyU = KeyboardKey(ebiten.KeyU)
+	KeyV = KeyboardKey(ebiten.KeyV)
+	KeyW = KeyboardKey(ebiten.KeyW)
+	KeyX = KeyboardKey(ebiten.KeyX)
+	KeyY = KeyboardKey(ebiten.KeyY)
+	KeyZ = KeyboardKey(ebiten.KeyZ)
+
+	// Dígitos da linha principal
+	KeyDigit0 = KeyboardKey(ebiten.KeyDigit0)
+	KeyDigit1 = KeyboardKey(ebiten.KeyDigit1)
+	KeyDigit2 = KeyboardKey(ebiten.KeyDigit2)
+	KeyDigit3 = KeyboardKey(ebiten.KeyDigit3)
+	KeyDigit4 = KeyboardKey(ebiten.KeyDigit4)
+	KeyDigit5 = KeyboardKey(ebiten.KeyDigit5)
+	KeyDigit6 = KeyboardKey(ebiten.KeyDigit6)
+	KeyDigit7 = KeyboardKey(ebiten.KeyDigit7)
+	KeyDigit8 = KeyboardKey(ebiten.KeyDigit8)
+	KeyDigit9 = KeyboardKey(ebiten.KeyDigit9)
+
+	// Setas
+	KeyArrowUp    = KeyboardKey(ebiten.KeyArrowUp)
+	KeyArrowDown  = KeyboardKey(ebiten.KeyArrowDown)
+	KeyArrowLeft  = KeyboardKey(ebiten.KeyArrowLeft)
+	KeyArrowRight = KeyboardKey(ebiten.KeyArrowRight)
+
+	// Edição e navegação
+	KeySpace     = KeyboardKey(ebiten.KeySpace)
+	KeyEnter     = KeyboardKey(ebiten.KeyEnter)
+	KeyEscape    = KeyboardKey(ebiten.KeyEscape)
+	KeyBackspace = KeyboardKey(ebiten.KeyBackspace)
+	KeyTab       = KeyboardKey(ebiten.KeyTab)
+	KeyDelete    = KeyboardKey(ebiten.KeyDelete)
+	KeyInsert    = KeyboardKey(ebiten.KeyInsert)
+	KeyHome      = KeyboardKey(ebiten.KeyHome)
+	KeyEnd       = KeyboardKey(ebiten.KeyEnd)
+	KeyPageUp    = KeyboardKey(ebiten.KeyPageUp)
+	KeyPageDown  = KeyboardKey(ebiten.KeyPageDown)
+
+	// Modificadores (KeyShift, KeyControl, KeyAlt e KeyMeta valem para qualquer lado)
+	KeyShift        = KeyboardKey(ebiten.KeyShift)
+	KeyShiftLeft    = KeyboardKey(ebiten.KeyShiftLeft)
+	KeyShiftRight   = KeyboardKey(ebiten.KeyShiftRight)
+	KeyControl      = KeyboardKey(ebiten.KeyControl)
+	KeyControlLeft  = KeyboardKey(ebiten.KeyControlLeft)
+	KeyControlRight = KeyboardKey(ebiten.KeyControlRight)
+	KeyAlt          = KeyboardKey(ebiten.KeyAlt)
+	KeyAltLeft      = KeyboardKey(ebiten.KeyAltLeft)
+	KeyAltRight     = KeyboardKey(ebiten.KeyAltRight)
+	KeyMeta         = KeyboardKey(ebiten.KeyMeta)
+	KeyMetaLeft     = KeyboardKey(ebiten.KeyMetaLeft)
+	KeyMetaRight    = KeyboardKey(ebiten.KeyMetaRight)
+	KeyCapsLock     = KeyboardKey(ebiten.KeyCapsLock)
+
+	// Pontuação
+	KeyMinus        = KeyboardKey(ebiten.KeyMinus)
+	KeyEqual        = KeyboardKey(ebiten.KeyEqual)
+	KeyComma        = KeyboardKey(ebiten.KeyComma)
+	KeyPeriod       = KeyboardKey(ebiten.KeyPeriod)
+	KeySlash        = KeyboardKey(ebiten.KeySlash)
+	KeyBackslash    = KeyboardKey(ebiten.KeyBackslash)
+	KeySemicolon    = KeyboardKey(ebiten.KeySemicolon)
+	KeyQuote        = KeyboardKey(ebiten.KeyQuote)
+	KeyBackquote    = KeyboardKey(ebiten.KeyBackquote)
+	KeyBracketLeft  = KeyboardKey(ebiten.KeyBracketLeft)
+	KeyBracketRight = KeyboardKey(ebiten.KeyBracketRight)
+
+	// Teclas de função
+	KeyF1  = KeyboardKey(ebiten.KeyF1)
+	KeyF2  = KeyboardKey(ebiten.KeyF2)
+	KeyF3  = KeyboardKey(ebiten.KeyF3)
+	KeyF4  = KeyboardKey(ebiten.KeyF4)
+	KeyF5  = KeyboardKey(ebiten.KeyF5)
+	KeyF6  = KeyboardKey(ebiten.KeyF6)
+	KeyF7  = KeyboardKey(ebiten.KeyF7)
+	KeyF8  = KeyboardKey(ebiten.KeyF8)
+	KeyF9  = KeyboardKey(ebiten.KeyF9)
+	KeyF10 = KeyboardKey(ebiten.KeyF10)
+	KeyF11 = KeyboardKey(ebiten.KeyF11)
+	KeyF12 = KeyboardKey(ebiten.KeyF12)
+
+	// Teclado numérico
+	KeyNumpad0        = KeyboardKey(ebiten.KeyNumpad0)
+	KeyNumpad1        = KeyboardKey(ebiten.KeyNumpad1)
+	KeyNumpad2        = KeyboardKey(ebiten.KeyNumpad2)
+	KeyNumpad3        = KeyboardKey(ebiten.KeyNumpad3)
+	KeyNumpad4        = KeyboardKey(ebiten.KeyNumpad4)
+	KeyNumpad5        = KeyboardKey(ebiten.KeyNumpad5)
+	KeyNumpad6        = KeyboardKey(ebiten.KeyNumpad6)
+	KeyNumpad7        = KeyboardKey(ebiten.KeyNumpad7)
+	KeyNumpad8        = KeyboardKey(ebiten.KeyNumpad8)
+	KeyNumpad9        = KeyboardKey(ebiten.KeyNumpad9)
+	KeyNumpadAdd      = KeyboardKey(ebiten.KeyNumpadAdd)
+	KeyNumpadSubtract = KeyboardKey(ebiten.KeyNumpadSubtract)
+	KeyNumpadMultiply = KeyboardKey(ebiten.KeyNumpadMultiply)
+	KeyNumpadDivide   = KeyboardKey(ebiten.KeyNumpadDivide)
+	KeyNumpadDecimal  = KeyboardKey(ebiten.KeyNumpadDecimal)
+	KeyNumpadEnter    = KeyboardKey(ebiten.KeyNumpadEnter)
+)
+
+// Estado global do teclado
+var (
+	lastKey      string
+	lastKeyCode  KeyboardKey = -1
+	keyIsPressed bool
+
+	keyPressedFn  func()
+	keyReleasedFn func()
+	keyTypedFn    func()
+)
+
+// KeyIsDown retorna se a tecla está pressionada neste momento
+// Útil para vários controles simultâneos, como movimentação com setas
+// Similar à função keyIsDown() do p5.js
+// Usa o quadro de entrada atual, então também vê teclas reproduzidas e simuladas.
+func KeyIsDown(k KeyboardKey) bool {
+	return containsKey(currentInput.Keys, k)
+}
+
+// KeyIsPressed retorna se alguma tecla está pressionada
+// Similar à variável keyIsPressed do p5.js/Processing
+func KeyIsPressed() bool { return keyIsPressed }
+
+// Key retorna a última tecla pressionada ou caractere digitado
+// Para teclas que geram texto é o próprio caractere (respeitando Shift e o layout
+// do teclado); para as demais é o nome da tecla, como "Enter" ou "ArrowLeft".
+// Se várias teclas de texto forem pressionadas no mesmo quadro, keyPressed recebe o
+// nome de cada uma e os caracteres chegam, na ordem digitada, por KeyTyped.
+// Similar à variável key do p5.js/Processing
+func Key() string { return lastKey }
+
+// KeyCode retorna a última tecla física pressionada, ou -1 se nenhuma foi pressionada
+// Similar à variável keyCode do p5.js/Processing
+func KeyCode() KeyboardKey { return lastKeyCode }
+
+// KeyPressed registra uma função chamada quando uma tecla é pressionada
+// Similar à função keyPressed() do p5.js/Processing
+func KeyPressed(f func()) { keyPressedFn = f }
+
+// KeyReleased registra uma função chamada quando uma tecla é solta
+// Similar à função keyReleased() do p5.js/Processing
+func KeyReleased(f func()) { keyReleasedFn = f }
+
+// KeyTyped registra uma função chamada para cada caractere digitado
+// Durante a chamada, Key() retorna o caractere. Teclas sem texto, como setas
+// e modificadores, não disparam KeyTyped.
+// Similar à função keyTyped() do p5.js/Processing
+func KeyTyped(f func()) { keyTypedFn = f }
+
+// String retorna o nome da tecla, como "A", "Space" ou "ArrowLeft"
+func (k KeyboardKey) String() string {
+	return ebiten.Key(k).String()
+}
+
+// MarshalText implementa encoding.TextMarshaler gravando o nome da tecla
+// As gravações de entrada guardam nomes, e não números, porque o Ebiten numera as teclas
+// em ordem alfabética e uma tecla nova muda os números das seguintes.
+func (k KeyboardKey) MarshalText() ([]byte, error) {
+	return ebiten.Key(k).MarshalText()
+}
+
+// UnmarshalText implementa encoding.TextUnmarshaler a partir do nome da tecla
+func (k *KeyboardKey) UnmarshalText(text []byte) error {
+	var key ebiten.Key
+	if err := key.UnmarshalText(text); err != nil {
+		return newError(ErrInvalidArgument, "tecla desconhecida: %q", text)
+	}
+	*k = KeyboardKey(key)
+	return nil
+}
+
+// GobEncode implementa gob.GobEncoder com o nome da tecla, como MarshalText
+func (k KeyboardKey) GobEncode() ([]byte, error) {
+	return k.MarshalText()
+}
+
+// GobDecode implementa gob.GobDecoder a partir do nome da tecla
+func (k *KeyboardKey) GobDecode(data []byte) error {
+	return k.UnmarshalText(data)
+}
+
+// pollPressedKeys retorna as teclas pressionadas nos dispositivos reais
+func pollPressedKeys() []ebiten.Key {
+	return inpututil.AppendPressedKeys(nil)
+}
+
+// updateKeyboard deriva o estado e os eventos do teclado a partir do quadro de entrada
+// atual e do anterior e dispara os callbacks registrados
+func updateKeyboard(cur, prev *inputFrame) {
+	keyIsPressed = len(cur.Keys) > 0
+	chars := []rune(cur.Chars)
+
+	var pressed []KeyboardKey
+	textKeys := 0
+	for _, k := range cur.Keys {
+		if !containsKey(prev.Keys, k) {
+			pressed = append(pressed, k)
+			if k.producesText() {
+				textKeys++
+			}
+		}
+	}
+
+	for _, k := range pressed {
+		lastKeyCode = k
+		lastKey = k.String()
+		// O caractere digitado tem precedência sobre o nome da tecla. Ele só pode ser
+		// atribuído com segurança quando uma única tecla de texto foi pressionada no
+		// quadro; com várias, não há como saber qual gerou cada caractere.
+		if textKeys == 1 && len(chars) > 0 && k.producesText() {
+			lastKey = string(chars[len(chars)-1])
+		}
+		callHandler("keyPressed", keyPressedFn)
+	}
+
+	for _, k := range prev.Keys {
+		if !containsKey(cur.Keys, k) {
+			callHandler("keyReleased", keyReleasedFn)
+		}
+	}
+
+	for _, r := range chars {
+		lastKey = string(r)
+		callHandler("keyTyped", keyTypedFn)
+	}
+}
+
+// producesText retorna se a tecla gera um caractere ao ser digitada
+// (letras, dígitos, espaço, pontuação e os dígitos e operadores do teclado numérico)
+func (k KeyboardKey) producesText() bool {
+	switch {
+	case k >= KeyA && k <= KeyZ, k >= KeyDigit0 && k <= KeyDigit9, k >= KeyNumpad0 && k <= KeyNumpad9:
+		return true
+	}
+	switch k {
+	case KeySpace, KeyMinus, KeyEqual, KeyComma, KeyPeriod, KeySlash, KeyBackslash,
+		KeySemicolon, KeyQuote, KeyBackquote, KeyBracketLeft, KeyBracketRight,
+		KeyNumpadAdd, KeyNumpadSubtract, KeyNumpadMultiply, KeyNumpadDivide, KeyNumpadDecimal:
+		return true
+	}
+	return false
+}
+
+// containsKey retorna se a tecla está na lista
+func containsKey(keys []KeyboardKey, key KeyboardKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+/*
+Projeto: GoSketch - Ciclo de Vida
+Descrição: Preload de assets antes do setup (com progresso), encerramento programático
+com Exit/ExitWithError, por cancelamento do contexto de RunContext ou pelo limite de
+RunFrames, e o hook OnExit, disparado também ao fechar a janela e por SIGINT
+*/
+
+package gosketch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Estado global do ciclo de vida
+var (
+	preloadFn         func()
+	preloadProgressFn func(loaded, total int, asset string)
+	preloading        bool
+	preloadErrors     []error
+	assetsLoaded      int
+	assetsTotal       int        // Número de assets informado por PreloadTotal (0 = desconhecido)
+	preloadMu         sync.Mutex // Protege o progresso e os erros do preload
+
+	onExitFn      func()
+	exitMu        sync.Mutex // Protege exitRequested e exitErr
+	exitRequested bool
+	exitErr       error
+	interrupted   chan os.Signal
+
+	runCtx     context.Context // Contexto de RunContext
+	frameLimit int             // Último quadro de RunFrames (0 = sem limite)
+	loopRan    bool            // O loop do Ebiten já foi iniciado neste processo
+)
+
+// Preload registra uma função executada antes de Setup para carregar assets
+// Erros reportados durante o preload (por exemplo, uma imagem que não existe)
+// fazem Run retornar um erro em vez de abrir a janela.
+// Similar à função preload() do p5.js
+func Preload(f func()) {
+	preloadFn = f
+}
+
+// PreloadProgress registra uma função chamada a cada asset carregado durante o preload,
+// com o número de assets carregados até o momento, o total informado por PreloadTotal
+// (0 se desconhecido) e o nome do último
+func PreloadProgress(f func(loaded, total int, asset string)) {
+	preloadProgressFn = f
+}
+
+// PreloadTotal informa quantos assets o preload vai carregar, para que
+// PreloadProgress possa exibir o progresso como fração do total
+func PreloadTotal(n int) {
+	if n < 0 {
+		reportError(newError(ErrInvalidArgument, "total de assets inválido: %d - deve ser não-negativo", n))
+		return
+	}
+	preloadMu.Lock()
+	defer preloadMu.Unlock()
+	assetsTotal = n
+}
+
+// trackAsset registra o carregamento de um asset para o progresso do preload
+// Assets carregados por goroutines durante o preload também contam.
+func trackAsset(name string) {
+	preloadMu.Lock()
+	if !preloading {
+		preloadMu.Unlock()
+		return
+	}
+	assetsLoaded++
+	loaded, total, progress := assetsLoaded, assetsTotal, preloadProgressFn
+	preloadMu.Unlock()
+
+	// O callback roda fora do lock, pois pode carregar outros assets
+	if progress != nil {
+		progress(loaded, total, name)
+	}
+}
+
+// OnExit registra uma função chamada quando o sketch termina, seja por Exit,
+// ExitWithError, fechamento da janela ou SIGINT (Ctrl+C). Use-a para salvar estado;
+// gravações em andamento são finalizadas automaticamente logo depois.
+func OnExit(f func()) {
+	onExitFn = f
+}
+
+// Exit encerra o sketch ao fim do quadro atual, fazendo Run retornar nil
+// Similar à função exit() do Processing
+// Pode ser chamada de qualquer goroutine.
+func Exit() {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitRequested = true
+}
+
+// ExitWithError encerra o sketch ao fim do quadro atual, fazendo Run retornar err
+// Pode ser chamada de qualquer goroutine.
+func ExitWithError(err error) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitRequested = true
+	exitErr = err
+}
+
+// resetRunState limpa o estado de encerramento e de preload
+// Serve aos testes e a uma nova tentativa depois que preload ou setup falharam, antes
+// de o loop começar; depois que o loop do Ebiten rodou, Run não pode ser chamada de
+// novo (veja runSketch).
+func resetRunState() {
+	exitMu.Lock()
+	exitRequested, exitErr = false, nil
+	exitMu.Unlock()
+
+	preloadMu.Lock()
+	preloadErrors, assetsLoaded = nil, 0
+	preloadMu.Unlock()
+}
+
+// runPreload executa a função de preload, retornando os erros reportados durante ela
+func runPreload() (err error) {
+	if preloadFn == nil {
+		return nil
+	}
+
+	setPreloading(true)
+	defer func() {
+		setPreloading(false)
+		if r := recover(); r != nil {
+			err = newError(ErrPanic, "pânico durante preload: %v", r)
+			reportError(err)
+		}
+	}()
+
+	preloadFn()
+	preloadFn = nil
+
+	preloadMu.Lock()
+	defer preloadMu.Unlock()
+	if len(preloadErrors) > 0 {
+		return fmt.Errorf(tr("falha ao carregar assets no preload: %w"), errors.Join(preloadErrors...))
+	}
+	return nil
+}
+
+// setPreloading marca o início ou o fim do preload
+func setPreloading(active bool) {
+	preloadMu.Lock()
+	defer preloadMu.Unlock()
+	preloading = active
+}
+
+// recordPreloadError guarda erros reportados durante o preload
+func recordPreloadError(err error) {
+	preloadMu.Lock()
+	defer preloadMu.Unlock()
+	if preloading {
+		preloadErrors = append(preloadErrors, err)
+	}
+}
+
+// watchInterrupt passa a tratar SIGINT como um pedido de encerramento
+func watchInterrupt() {
+	interrupted = make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+}
+
+// stopWatchingInterrupt restaura o comportamento padrão de SIGINT
+func stopWatchingInterrupt() {
+	signal.Stop(interrupted)
+}
+
+// exitPending informa se o sketch deve terminar: por Exit, SIGINT, cancelamento
+// do contexto de RunContext ou por atingir o limite de RunFrames
+func exitPending() bool {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+
+	if exitRequested {
+		return true
+	}
+
+	select {
+	case <-interrupted:
+		exitRequested = true
+	default:
+	}
+
+	if runCtx != nil && runCtx.Err() != nil {
+		exitRequested = true
+		exitErr = fmt.Errorf(tr("sketch interrompido pelo contexto: %w"), runCtx.Err())
+	}
+
+	if frameLimitReached() || (frameLimit > 0 && !isLooping && redrawCount == 0) {
+		exitRequested = true
+	}
+	return exitRequested
+}
+
+// frameLimitReached informa se RunFrames já desenhou todos os quadros pedidos
+// Draw deixa de desenhar a partir daí, mesmo que o quadro seja devido antes do
+// próximo Update (por exemplo, com FrameRate acima de UpdateRate ou FrameRate(0)).
+func frameLimitReached() bool {
+	return frameLimit > 0 && frameCount >= frameLimit
+}
+
+// exitError retorna o erro com que o sketch deve terminar, se houver
+func exitError() error {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	return exitErr
+}
+
+// runExitHooks chama OnExit e finaliza as gravações que ainda estiverem em andamento
+func runExitHooks() {
+	callHandler("onExit", onExitFn)
+
+	if IsRecording() {
+		if err := StopRecording(); err != nil {
+			reportError(err)
+		}
+	}
+	if isInputRecord {
+		if err := StopInputRecording(); err != nil {
+			reportError(err)
+		}
+	}
+}
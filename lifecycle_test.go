@@ -0,0 +1,44 @@
+package gosketch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunOncePerProcess(t *testing.T) {
+	t.Cleanup(func() {
+		loopRan = false
+		resetRunState()
+	})
+
+	// Depois que o loop do Ebiten rodou, nenhuma função de execução o inicia de novo
+	loopRan = true
+	for name, run := range map[string]func() error{
+		"Run":       Run,
+		"RunFrames": func() error { return RunFrames(1) },
+	} {
+		err := run()
+		if !errors.Is(err, ErrInvalidState) {
+			t.Errorf("%s na segunda execução = %v; esperado ErrInvalidState", name, err)
+		}
+		var sketchErr *SketchError
+		if !errors.As(err, &sketchErr) {
+			t.Errorf("%s retornou %T; esperado *SketchError", name, err)
+		}
+	}
+}
+
+func TestRunRetryAfterSetupFailure(t *testing.T) {
+	captureErrors(t, func(error) {})
+	previous := setupFn
+	t.Cleanup(func() { setupFn = previous })
+
+	// Uma falha antes do loop não conta como execução
+	setupFn = func() { panic("falha no setup") }
+	if err := RunFrames(1); !errors.Is(err, ErrPanic) {
+		t.Fatalf("RunFrames = %v; esperado ErrPanic", err)
+	}
+	if loopRan {
+		t.Error("falha no setup marcou o loop como executado")
+	}
+}
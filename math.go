@@ -60,11 +60,4 @@ func Lerp(start, stop, amt float64) float64 {
 func Dist(x1, y1, x2, y2 float64) float64 {
 	return math.Sqrt((x2-x1)*(x2-x1) + (y2-y1)*(y2-y1))
 }
-
-// Random returns a random number between min and max
-func Random(min, max float64) float64 {
-	return min + math.Mod(math.Abs(math.Sin(rand)), max-min)
-}
-
-// Private variable for quick randomness
-var rand float64 = 9.7 
\ No newline at end of file
+ 
\ No newline at end of file
@@ -0,0 +1,193 @@
+/*
+Projeto: GoSketch - Mensagens em Inglês
+Descrição: Traduções para inglês das mensagens da biblioteca, indexadas pela mensagem
+original em pt-BR (veja i18n.go). Ao criar uma mensagem nova, acrescente-a aqui.
+*/
+
+package gosketch
+
+// messagesEN contém as traduções para inglês
+var messagesEN = map[string]string{
+	// Categorias de erro (errors.go)
+	"canvas não inicializado":           "canvas not initialized",
+	"dimensões inválidas":               "invalid dimensions",
+	"cor inválida":                      "invalid color",
+	"argumento inválido":                "invalid argument",
+	"argumento nulo":                    "nil argument",
+	"coordenadas fora dos limites":      "coordinates out of bounds",
+	"fonte indisponível":                "font unavailable",
+	"falha ao carregar asset":           "failed to load asset",
+	"falha ao escrever arquivo":         "failed to write file",
+	"formato não suportado":             "unsupported format",
+	"operação inválida no estado atual": "invalid operation in the current state",
+	"erro de gravação":                  "recording error",
+	"pânico no código do sketch":        "panic in sketch code",
+
+	// Tratamento de erros
+	"ERRO NA GOSKETCH: %v\n": "GOSKETCH ERROR: %v\n",
+	"Stack trace:\n%s\n":     "Stack trace:\n%s\n",
+	"ERRO: %v\n":             "ERROR: %v\n",
+	"%v (repetido mais %d vezes desde o último aviso)": "%v (repeated %d more times since the last report)",
+	"modo estrito: %w": "strict mode: %w",
+	"idioma não suportado: '%s' - use pt-BR ou en": "unsupported language: '%s' - use pt-BR or en",
+
+	// Canvas, ciclo de vida e janela
+	"dimensões de canvas inválidas: %dx%d - as dimensões devem ser positivas":                            "invalid canvas dimensions: %dx%d - dimensions must be positive",
+	"canvas não criado. Use CreateCanvas no setup":                                                       "canvas not created. Use CreateCanvas in setup",
+	"canvas não inicializado ao definir layout":                                                          "canvas not initialized when computing layout",
+	"tentativa de definir background sem canvas inicializado":                                            "attempt to set background without an initialized canvas",
+	"tentativa de renderizar sem canvas inicializado":                                                    "attempt to render without an initialized canvas",
+	"tentativa de renderizar uma shape nula":                                                             "attempt to render a nil shape",
+	"tentativa de obter largura sem canvas inicializado":                                                 "attempt to get width without an initialized canvas",
+	"tentativa de obter altura sem canvas inicializado":                                                  "attempt to get height without an initialized canvas",
+	"tentativa de redimensionar canvas sem canvas inicializado":                                          "attempt to resize the canvas without an initialized canvas",
+	"não é possível redimensionar o canvas durante uma gravação - use StopRecording antes":               "cannot resize the canvas while recording - call StopRecording first",
+	"não é possível alterar a densidade de pixels durante uma gravação - use StopRecording antes":        "cannot change pixel density while recording - call StopRecording first",
+	"densidade de pixels inválida: %.2f - a densidade deve ser positiva":                                 "invalid pixel density: %.2f - density must be positive",
+	"política de escala inválida: %d - use LETTERBOX, STRETCH ou INTEGER_SCALE":                          "invalid scaling policy: %d - use LETTERBOX, STRETCH or INTEGER_SCALE",
+	"taxa de atualização inválida: %d - a taxa deve ser positiva":                                        "invalid update rate: %d - the rate must be positive",
+	"contexto nulo passado para RunContext":                                                              "nil context passed to RunContext",
+	"número de quadros inválido para RunFrames: %d - deve ser positivo":                                  "invalid frame count for RunFrames: %d - must be positive",
+	"o sketch já foi executado - Run, RunContext e RunFrames só podem ser chamadas uma vez por processo": "the sketch has already run - Run, RunContext and RunFrames can only be called once per process",
+	"sketch interrompido pelo contexto: %w":                                                              "sketch stopped by context: %w",
+	"falha ao carregar assets no preload: %w":                                                            "failed to load assets in preload: %w",
+	"total de assets inválido: %d - deve ser não-negativo":                                               "invalid asset total: %d - must be non-negative",
+	"pânico durante setup: %v":                                                                           "panic during setup: %v",
+	"pânico durante preload: %v":                                                                         "panic during preload: %v",
+	"pânico durante draw: %v":                                                                            "panic during draw: %v",
+	"pânico durante renderização: %v":                                                                    "panic during rendering: %v",
+	"tentativa de enfileirar um comando de desenho nulo":                                                 "attempt to queue a nil draw command",
+	"pânico durante %s: %v":                                                                              "panic during %s: %v",
+
+	// Formas e estilo
+	"espessura de contorno inválida: %.2f - deve ser não-negativa":                          "invalid stroke weight: %.2f - must be non-negative",
+	"raio inválido para elipse: rx=%.2f, ry=%.2f - os raios devem ser positivos":            "invalid radius for ellipse: rx=%.2f, ry=%.2f - radii must be positive",
+	"raio inválido para círculo: %.2f - o raio deve ser positivo":                           "invalid radius for circle: %.2f - the radius must be positive",
+	"dimensões inválidas para retângulo: w=%.2f, h=%.2f - as dimensões devem ser positivas": "invalid rectangle dimensions: w=%.2f, h=%.2f - dimensions must be positive",
+	"tamanho inválido para quadrado: %.2f - o tamanho deve ser positivo":                    "invalid square size: %.2f - the size must be positive",
+	"valor int fora do range para cor: %d - deve estar entre 0-255":                         "int value out of range for color: %d - must be between 0-255",
+	"tipo de cor inválido dentro de ColorValue: %T":                                         "invalid color type inside ColorValue: %T",
+
+	// Imagens e pixels
+	"erro ao abrir imagem '%s': %w":                                    "error opening image '%s': %w",
+	"erro ao ler informações da imagem '%s': %w":                       "error reading image info '%s': %w",
+	"erro ao decodificar imagem '%s': %w":                              "error decoding image '%s': %w",
+	"erro ao decodificar imagem: %w":                                   "error decoding image: %w",
+	"erro ao recarregar imagem '%s': %w":                               "error reloading image '%s': %w",
+	"tentativa de carregar imagem '%s' de um sistema de arquivos nulo": "attempt to load image '%s' from a nil file system",
+	"tentativa de carregar imagem de um reader nulo":                   "attempt to load image from a nil reader",
+	"tentativa de carregar imagem de dados vazios":                     "attempt to load image from empty data",
+	"tentativa de desenhar imagem sem canvas inicializado":             "attempt to draw image without an initialized canvas",
+	"tentativa de desenhar imagem nula":                                "attempt to draw a nil image",
+	"modo de imagem inválido: %d - use CORNER, CORNERS ou CENTER":      "invalid image mode: %d - use CORNER, CORNERS or CENTER",
+	"tentativa de obter região sem canvas inicializado":                "attempt to get region without an initialized canvas",
+	"dimensões de região inválidas: %dx%d":                             "invalid region dimensions: %dx%d",
+	"tentativa de copiar região sem canvas inicializado":               "attempt to copy region without an initialized canvas",
+	"dimensões de cópia inválidas: origem %dx%d, destino %dx%d":        "invalid copy dimensions: source %dx%d, destination %dx%d",
+	"tentativa de obter pixel sem canvas inicializado":                 "attempt to get pixel without an initialized canvas",
+	"tentativa de definir pixel sem canvas inicializado":               "attempt to set pixel without an initialized canvas",
+	"coordenadas de pixel fora dos limites: (%d, %d)":                  "pixel coordinates out of bounds: (%d, %d)",
+	"tentativa de carregar pixels sem canvas inicializado":             "attempt to load pixels without an initialized canvas",
+	"tentativa de atualizar pixels sem canvas inicializado":            "attempt to update pixels without an initialized canvas",
+	"tentativa de atualizar pixels sem carregar pixels primeiro":       "attempt to update pixels without loading pixels first",
+	"dimensões de recorte inválidas: %dx%d":                            "invalid crop dimensions: %dx%d",
+	"dimensões de redimensionamento inválidas: %dx%d":                  "invalid resize dimensions: %dx%d",
+	"dimensões de imagem inválidas: %dx%d":                             "invalid image dimensions: %dx%d",
+	"tentativa de salvar imagem sem canvas inicializado":               "attempt to save image without an initialized canvas",
+	"erro ao criar arquivo '%s': %w":                                   "error creating file '%s': %w",
+	"limite de cache de imagens inválido: %d - deve ser não-negativo":  "invalid image cache limit: %d - must be non-negative",
+
+	// Animações e gravação
+	"erro ao abrir animação '%s': %w":                                   "error opening animation '%s': %w",
+	"erro ao decodificar animação '%s': %w":                             "error decoding animation '%s': %w",
+	"erro ao decodificar animação: %w":                                  "error decoding animation: %w",
+	"tentativa de carregar animação de um reader nulo":                  "attempt to load animation from a nil reader",
+	"índice de quadro fora dos limites: %d (a animação tem %d quadros)": "frame index out of bounds: %d (the animation has %d frames)",
+	"GIF sem quadros":                               "GIF has no frames",
+	"chunk acTL inválido":                           "invalid acTL chunk",
+	"chunk fdAT inesperado":                         "unexpected fdAT chunk",
+	"chunk IHDR inválido":                           "invalid IHDR chunk",
+	"chunk fcTL inválido":                           "invalid fcTL chunk",
+	"chunk '%s' truncado":                           "truncated '%s' chunk",
+	"erro no quadro %d: %w":                         "error in frame %d: %w",
+	"assinatura PNG inválida":                       "invalid PNG signature",
+	"já existe uma gravação em andamento para '%s'": "a recording is already in progress for '%s'",
+	"formato de gravação não suportado para '%s' - use .gif, .png ou .apng": "unsupported recording format for '%s' - use .gif, .png or .apng",
+	"modo de paleta inválido: %d - use PalettePerFrame ou PaletteShared":    "invalid palette mode: %d - use PalettePerFrame or PaletteShared",
+	"nenhuma gravação em andamento":                                         "no recording in progress",
+	"gravação '%s' não capturou nenhum quadro":                              "recording '%s' captured no frames",
+	"erro ao codificar gravação '%s': %w":                                   "error encoding recording '%s': %w",
+
+	// Entrada
+	"já existe uma gravação de entrada em andamento para '%s'": "an input recording is already in progress for '%s'",
+	"nenhuma gravação de entrada em andamento":                 "no input recording in progress",
+	"erro ao escrever gravação de entrada '%s': %w":            "error writing input recording '%s': %w",
+	"erro ao abrir gravação de entrada '%s': %w":               "error opening input recording '%s': %w",
+	"erro ao ler gravação de entrada '%s': %w":                 "error reading input recording '%s': %w",
+	"versão de gravação de entrada não suportada em '%s': %d":  "unsupported input recording version in '%s': %d",
+	"tecla desconhecida: %q":                                   "unknown key: %q",
+
+	// Fontes e texto
+	"erro ao abrir fonte '%s': %w":                                            "error opening font '%s': %w",
+	"tentativa de carregar fonte '%s' de um sistema de arquivos nulo":         "attempt to load font '%s' from a nil file system",
+	"tentativa de carregar fonte de dados vazios":                             "attempt to load font from empty data",
+	"erro ao decodificar fonte '%s': %w":                                      "error decoding font '%s': %w",
+	"arquivo de fonte '%s' não contém fontes":                                 "font file '%s' contains no fonts",
+	"erro ao criar face da fonte '%s' no tamanho %.2f: %w":                    "error creating face for font '%s' at size %.2f: %w",
+	"erro ao abrir fonte bitmap '%s': %w":                                     "error opening bitmap font '%s': %w",
+	"tentativa de carregar fonte bitmap '%s' de um sistema de arquivos nulo":  "attempt to load bitmap font '%s' from a nil file system",
+	"dimensões de célula inválidas: %dx%d":                                    "invalid cell dimensions: %dx%d",
+	"erro ao decodificar fonte bitmap '%s': %w":                               "error decoding bitmap font '%s': %w",
+	"imagem da fonte bitmap '%s' é menor que uma célula":                      "bitmap font image '%s' is smaller than one cell",
+	"fonte bitmap '%s' não define caracteres ou páginas":                      "bitmap font '%s' defines no characters or pages",
+	"fonte bitmap '%s' tem página com id inválido: %d":                        "bitmap font '%s' has a page with invalid id: %d",
+	"erro ao carregar página '%s' da fonte bitmap '%s': %w":                   "error loading page '%s' of bitmap font '%s': %w",
+	"formato binário do BMFont não é suportado - exporte em texto ou XML":     "binary BMFont format is not supported - export as text or XML",
+	"tentativa de desenhar texto sem canvas inicializado":                     "attempt to draw text without an initialized canvas",
+	"tentativa de desenhar texto sem fonte disponível":                        "attempt to draw text without an available font",
+	"tentativa de medir texto sem fonte disponível":                           "attempt to measure text without an available font",
+	"caixa de texto inválida: %v - informe largura e altura positivas":        "invalid text box: %v - provide positive width and height",
+	"tamanho de texto inválido: %.2f - deve ser positivo":                     "invalid text size: %.2f - must be positive",
+	"entrelinha inválida: %.2f - deve ser não-negativa":                       "invalid leading: %.2f - must be non-negative",
+	"alinhamento horizontal inválido: %d - use LEFT, CENTER ou RIGHT":         "invalid horizontal alignment: %d - use LEFT, CENTER or RIGHT",
+	"alinhamento vertical inválido: %d - use TOP, CENTER, BASELINE ou BOTTOM": "invalid vertical alignment: %d - use TOP, CENTER, BASELINE or BOTTOM",
+	"fator de amostragem inválido: %.2f - deve ser positivo":                  "invalid sample factor: %.2f - must be positive",
+	"a fonte atual não possui contornos vetoriais":                            "the current font has no vector outlines",
+	"erro ao ler métricas da fonte '%s': %w":                                  "error reading metrics of font '%s': %w",
+	"caminho inválido: são necessários pelo menos 2 vértices, recebidos %d":   "invalid path: at least 2 vertices are required, got %d",
+	"raio inválido para caminho circular: %.2f - o raio deve ser positivo":    "invalid radius for circular path: %.2f - the radius must be positive",
+	"tentativa de desenhar texto em um caminho nulo":                          "attempt to draw text on a nil path",
+
+	// Rasterização em memória
+	"dimensões inválidas para a lista de desenho: %dx%d - as dimensões devem ser positivas":              "invalid display list dimensions: %dx%d - dimensions must be positive",
+	"função de desenho nula passada para RecordDisplayList":                                              "nil draw function passed to RecordDisplayList",
+	"RecordDisplayList não pode ser aninhada":                                                            "RecordDisplayList cannot be nested",
+	"número de workers inválido: %d - o número deve ser positivo":                                        "invalid number of workers: %d - the number must be positive",
+	"tentativa de rasterizar uma lista de desenho nula":                                                  "attempt to rasterize a nil display list",
+	"tamanho de bloco inválido: %d - o tamanho deve ser positivo":                                        "invalid tile size: %d - the size must be positive",
+	"dimensões inválidas para renderização em blocos: %dx%d - as dimensões devem ser positivas":          "invalid dimensions for tiled rendering: %dx%d - dimensions must be positive",
+	"escala inválida: %.2f - a escala deve ser positiva":                                                 "invalid scale: %.2f - the scale must be positive",
+	"RenderTiled requer uma função registrada com Draw":                                                  "RenderTiled requires a function registered with Draw",
+	"RenderTiled não pode ser chamada durante RecordDisplayList":                                         "RenderTiled cannot be called during RecordDisplayList",
+	"erro ao escrever arquivo '%s': %w":                                                                  "error writing file '%s': %w",
+	"falha ao gravar as formas do quadro":                                                                "failed to record the shapes of the frame",
+	"%s não é gravada por RecordDisplayList e foi ignorada - apenas formas e Background entram na lista": "%s is not recorded by RecordDisplayList and was ignored - only shapes and Background are recorded",
+	"erro ao codificar faixa %v: %w":                                                                     "error encoding band %v: %w",
+	"erro ao codificar imagem: %w":                                                                       "error encoding image: %w",
+	"erro ao escrever imagem: %w":                                                                        "error writing image: %w",
+	"a forma %T não implementa shapes.Transformable e não pode ser escalada":                             "shape %T does not implement shapes.Transformable and cannot be scaled",
+
+	// Unidades físicas
+	"formato de papel inválido: %.1fx%.1f mm - as dimensões devem ser positivas": "invalid paper size: %.1fx%.1f mm - dimensions must be positive",
+	"resolução inválida: %.2f dpi - a resolução deve ser positiva":               "invalid resolution: %.2f dpi - the resolution must be positive",
+	"orientação inválida: %d - use PORTRAIT ou LANDSCAPE":                        "invalid orientation: %d - use PORTRAIT or LANDSCAPE",
+	"unidade inválida: %d - use PX, MM, CM ou INCH":                              "invalid unit: %d - use PX, MM, CM or INCH",
+	"tentativa de obter dimensões sem canvas inicializado":                       "attempt to get dimensions without an initialized canvas",
+	"a forma %T não implementa shapes.Transformable e será desenhada em pixels":  "shape %T does not implement shapes.Transformable and will be drawn in pixels",
+
+	// Metadados de imagens
+	"erro ao abrir arquivo '%s': %w":                "error opening file '%s': %w",
+	"erro ao ler metadados de '%s': %w":             "error reading metadata from '%s': %w",
+	"erro ao ler o código do sketch: %w":            "error reading the sketch source: %w",
+	"arquivo '%s' não contém metadados do GoSketch": "file '%s' contains no GoSketch metadata",
+}
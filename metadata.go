@@ -0,0 +1,337 @@
+/*
+Projeto: GoSketch - Metadados de Imagens
+Descrição: Gravação, em blocos tEXt/iTXt dos PNGs salvos, das informações necessárias
+para reproduzir uma imagem (sementes, quadro, parâmetros, revisão e código do sketch)
+e leitura desses metadados com ReadSketchMetadata
+*/
+
+package gosketch
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Palavras-chave dos blocos de texto gravados no PNG
+const (
+	metaKeySoftware    = "Software"
+	metaKeyRandomSeed  = "gosketch:random-seed"
+	metaKeyNoiseSeed   = "gosketch:noise-seed"
+	metaKeyFrame       = "gosketch:frame"
+	metaKeyParams      = "gosketch:params"
+	metaKeyGitRevision = "gosketch:git-revision"
+	metaKeySource      = "gosketch:source"
+)
+
+// SketchMetadata são as informações gravadas nos PNGs para que a imagem possa
+// ser gerada novamente
+type SketchMetadata struct {
+	RandomSeed  int64             // Semente de Random; preenchida com GetRandomSeed() ao salvar
+	NoiseSeed   int64             // Semente de Noise; preenchida com GetNoiseSeed() ao salvar
+	Frame       int               // Quadro salvo; preenchido com FrameCount() ao salvar
+	Params      map[string]string // Valores dos parâmetros do sketch
+	GitRevision string            // Revisão do código; se vazia, a do build (vcs.revision)
+	Source      string            // Código do sketch; se vazio, lido de SourceFS ou do chamador
+
+	// SourceFS contém o código do sketch, normalmente um embed.FS com //go:embed *.go;
+	// seus arquivos .go são gravados em Source. Não é gravado no PNG.
+	SourceFS fs.FS
+}
+
+// Estado global dos metadados
+var saveMetadata *SketchMetadata // nil quando os metadados estão desativados
+
+// SaveMetadata ativa a gravação de metadados nos PNGs salvos por SaveImage e RenderTiled
+// As sementes de Random e Noise e o número do quadro são preenchidos no momento de salvar;
+// a revisão, quando não informada, vem das informações de build.
+// O código do sketch é lido agora, na ordem: Source, os arquivos .go de SourceFS e, por
+// último, o arquivo que chamou esta função. Esse último caso só funciona na máquina que
+// compilou o sketch, sem -trimpath, e grava apenas um arquivo; para sketches distribuídos
+// ou com vários arquivos, use SourceFS com um embed.FS.
+// Chame novamente para atualizar os valores (por exemplo, ao mudar um parâmetro).
+func SaveMetadata(meta SketchMetadata) {
+	meta.Params = maps.Clone(meta.Params)
+
+	switch {
+	case meta.Source != "":
+	case meta.SourceFS != nil:
+		source, err := readSourceFS(meta.SourceFS)
+		if err != nil {
+			reportError(newError(ErrLoadFailed, "erro ao ler o código do sketch: %w", err))
+		}
+		meta.Source = source
+	default:
+		if _, file, _, ok := runtime.Caller(1); ok {
+			if data, err := os.ReadFile(file); err == nil {
+				meta.Source = string(data)
+			}
+		}
+	}
+	meta.SourceFS = nil
+	saveMetadata = &meta
+}
+
+// readSourceFS concatena os arquivos .go de fsys, em ordem alfabética, cada um
+// precedido de um comentário com o seu caminho
+func readSourceFS(fsys fs.FS) (string, error) {
+	var source strings.Builder
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != ".go" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if source.Len() > 0 {
+			source.WriteString("\n")
+		}
+		source.WriteString("// " + name + "\n")
+		source.Write(data)
+		return nil
+	})
+	return source.String(), err
+}
+
+// NoSaveMetadata desativa a gravação de metadados nas imagens salvas
+func NoSaveMetadata() {
+	saveMetadata = nil
+}
+
+// currentMetadata retorna os metadados a gravar agora, ou nil se estiverem desativados
+func currentMetadata() *SketchMetadata {
+	if saveMetadata == nil {
+		return nil
+	}
+
+	meta := *saveMetadata
+	meta.RandomSeed = randomSeed
+	meta.NoiseSeed = noiseSeed
+	meta.Frame = frameCount
+	if meta.GitRevision == "" {
+		meta.GitRevision = buildRevision()
+	}
+	return &meta
+}
+
+// buildRevision retorna a revisão do controle de versão gravada no build, se houver
+// Revisões com alterações não commitadas recebem o sufixo "-dirty".
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// pngChunks converte os metadados em blocos tEXt (valores ASCII) e iTXt (UTF-8)
+func (meta *SketchMetadata) pngChunks() []pngChunk {
+	chunks := []pngChunk{
+		{"tEXt", textChunk(metaKeySoftware, "GoSketch")},
+		{"tEXt", textChunk(metaKeyRandomSeed, strconv.FormatInt(meta.RandomSeed, 10))},
+		{"tEXt", textChunk(metaKeyNoiseSeed, strconv.FormatInt(meta.NoiseSeed, 10))},
+		{"tEXt", textChunk(metaKeyFrame, strconv.Itoa(meta.Frame))},
+	}
+	if len(meta.Params) > 0 {
+		// json.Marshal ordena as chaves, então a mesma configuração gera os mesmos bytes
+		if params, err := json.Marshal(meta.Params); err == nil {
+			chunks = append(chunks, pngChunk{"iTXt", internationalTextChunk(metaKeyParams, string(params), false)})
+		}
+	}
+	if meta.GitRevision != "" {
+		chunks = append(chunks, pngChunk{"tEXt", textChunk(metaKeyGitRevision, meta.GitRevision)})
+	}
+	if meta.Source != "" {
+		chunks = append(chunks, pngChunk{"iTXt", internationalTextChunk(metaKeySource, meta.Source, true)})
+	}
+	return chunks
+}
+
+// textChunk monta os dados de um bloco tEXt: palavra-chave, separador nulo e texto
+func textChunk(key, value string) []byte {
+	return []byte(key + "\x00" + value)
+}
+
+// internationalTextChunk monta os dados de um bloco iTXt com texto UTF-8,
+// opcionalmente comprimido com zlib
+func internationalTextChunk(key, value string, compress bool) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(key)
+	buf.WriteByte(0)
+	if compress {
+		buf.Write([]byte{1, 0}) // Comprimido, método zlib
+	} else {
+		buf.Write([]byte{0, 0})
+	}
+	buf.WriteByte(0) // Sem idioma
+	buf.WriteByte(0) // Sem palavra-chave traduzida
+
+	if compress {
+		zw := zlib.NewWriter(&buf)
+		io.WriteString(zw, value)
+		zw.Close()
+	} else {
+		buf.WriteString(value)
+	}
+	return buf.Bytes()
+}
+
+// ReadSketchMetadata lê os metadados gravados por SaveImage ou RenderTiled em um PNG
+// Apenas o início do arquivo é lido: os metadados ficam antes dos dados da imagem,
+// então a leitura para no primeiro bloco IDAT, mesmo em pôsteres de vários gigabytes.
+func ReadSketchMetadata(path string) (*SketchMetadata, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, newError(ErrLoadFailed, "erro ao abrir arquivo '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	chunks, err := readPNGTextChunks(bufio.NewReader(file))
+	if err != nil {
+		return nil, newError(ErrUnsupported, "erro ao ler metadados de '%s': %w", path, err)
+	}
+
+	meta := &SketchMetadata{}
+	found := false
+	for _, chunk := range chunks {
+		var key, value string
+		switch chunk.typ {
+		case "tEXt":
+			k, v, _ := bytes.Cut(chunk.data, []byte{0})
+			key, value = string(k), string(v)
+		case "iTXt":
+			key, value, err = parseInternationalText(chunk.data)
+			if err != nil {
+				return nil, newError(ErrLoadFailed, "erro ao ler metadados de '%s': %w", path, err)
+			}
+		default:
+			continue
+		}
+
+		if meta.set(key, value) {
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, newError(ErrLoadFailed, "arquivo '%s' não contém metadados do GoSketch", path)
+	}
+	return meta, nil
+}
+
+// readPNGTextChunks lê os blocos tEXt e iTXt de um PNG até o primeiro IDAT,
+// pulando os demais blocos sem carregá-los
+func readPNGTextChunks(r io.Reader) ([]pngChunk, error) {
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(r, signature); err != nil || string(signature) != pngSignature {
+		return nil, errors.New(tr("assinatura PNG inválida"))
+	}
+
+	var chunks []pngChunk
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return chunks, nil
+			}
+			return nil, err
+		}
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		typ := string(header[4:8])
+		if typ == "IDAT" || typ == "IEND" {
+			return chunks, nil
+		}
+
+		if typ != "tEXt" && typ != "iTXt" {
+			if _, err := io.CopyN(io.Discard, r, length+4); err != nil { // Dados + CRC
+				return nil, fmt.Errorf(tr("chunk '%s' truncado"), typ)
+			}
+			continue
+		}
+		// LimitReader evita alocar o tamanho declarado antes de saber se os dados existem
+		data, err := io.ReadAll(io.LimitReader(r, length))
+		if err != nil || int64(len(data)) != length {
+			return nil, fmt.Errorf(tr("chunk '%s' truncado"), typ)
+		}
+		if _, err := io.CopyN(io.Discard, r, 4); err != nil {
+			return nil, fmt.Errorf(tr("chunk '%s' truncado"), typ)
+		}
+		chunks = append(chunks, pngChunk{typ: typ, data: data})
+	}
+}
+
+// parseInternationalText extrai a palavra-chave e o texto de um bloco iTXt
+func parseInternationalText(chunk []byte) (string, string, error) {
+	key, rest, _ := bytes.Cut(chunk, []byte{0})
+	if len(rest) < 2 {
+		return string(key), "", nil
+	}
+	compressed := rest[0] == 1
+	rest = rest[2:]
+	_, rest, _ = bytes.Cut(rest, []byte{0}) // Idioma
+	_, rest, _ = bytes.Cut(rest, []byte{0}) // Palavra-chave traduzida
+
+	if !compressed {
+		return string(key), string(rest), nil
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(rest))
+	if err != nil {
+		return "", "", err
+	}
+	defer zr.Close()
+	text, err := io.ReadAll(zr)
+	if err != nil {
+		return "", "", err
+	}
+	return string(key), string(text), nil
+}
+
+// set atribui o valor de um bloco de texto ao campo correspondente,
+// informando se a palavra-chave pertence aos metadados do GoSketch
+func (meta *SketchMetadata) set(key, value string) bool {
+	switch key {
+	case metaKeyRandomSeed:
+		meta.RandomSeed, _ = strconv.ParseInt(value, 10, 64)
+	case metaKeyNoiseSeed:
+		meta.NoiseSeed, _ = strconv.ParseInt(value, 10, 64)
+	case metaKeyFrame:
+		meta.Frame, _ = strconv.Atoi(value)
+	case metaKeyParams:
+		json.Unmarshal([]byte(value), &meta.Params)
+	case metaKeyGitRevision:
+		meta.GitRevision = value
+	case metaKeySource:
+		meta.Source = value
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package gosketch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSaveMetadataSourceFS(t *testing.T) {
+	t.Cleanup(NoSaveMetadata)
+
+	sketch := fstest.MapFS{
+		"main.go":         {Data: []byte("package main\n")},
+		"scene/scene.go":  {Data: []byte("package scene\n")},
+		"assets/logo.png": {Data: []byte("não é código")},
+	}
+	SaveMetadata(SketchMetadata{SourceFS: sketch})
+
+	meta := currentMetadata()
+	want := "// main.go\npackage main\n\n// scene/scene.go\npackage scene\n"
+	if meta.Source != want {
+		t.Errorf("Source = %q; esperado %q", meta.Source, want)
+	}
+	if meta.SourceFS != nil {
+		t.Error("SourceFS mantido nos metadados gravados")
+	}
+}
+
+func TestSaveMetadataCallerSource(t *testing.T) {
+	t.Cleanup(NoSaveMetadata)
+
+	// Sem Source nem SourceFS, o código vem do arquivo que chamou SaveMetadata
+	SaveMetadata(SketchMetadata{})
+	if source := currentMetadata().Source; !strings.Contains(source, "func TestSaveMetadataCallerSource") {
+		t.Errorf("Source não contém o arquivo do chamador: %.60q", source)
+	}
+
+	SaveMetadata(SketchMetadata{Source: "explícito"})
+	if source := currentMetadata().Source; source != "explícito" {
+		t.Errorf("Source = %q; esperado o código informado", source)
+	}
+}
+
+func TestReadSketchMetadataStopsAtImageData(t *testing.T) {
+	meta := &SketchMetadata{
+		RandomSeed: 11,
+		NoiseSeed:  22,
+		Frame:      3,
+		Params:     map[string]string{"cor": "azul"},
+		Source:     "package main\n",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	writePNGChunk(&buf, "IHDR", make([]byte, 13))
+	writePNGChunk(&buf, "pHYs", pngPhysicalDimensions(300))
+	for _, chunk := range meta.pngChunks() {
+		writePNGChunk(&buf, chunk.typ, chunk.data)
+	}
+	// Um IDAT truncado: a leitura precisa parar antes de chegar aos dados
+	buf.Write([]byte{0x7f, 0xff, 0xff, 0xff, 'I', 'D', 'A', 'T', 1, 2, 3})
+
+	path := filepath.Join(t.TempDir(), "meta.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadSketchMetadata(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RandomSeed != 11 || got.NoiseSeed != 22 || got.Frame != 3 ||
+		got.Params["cor"] != "azul" || got.Source != meta.Source {
+		t.Errorf("metadados lidos = %+v; esperado %+v", got, meta)
+	}
+}
+
+func TestReadSketchMetadataTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	writePNGChunk(&buf, "IHDR", make([]byte, 13))
+	// Um tEXt que declara mais dados do que o arquivo contém
+	buf.Write([]byte{0x7f, 0xff, 0xff, 0xff, 't', 'E', 'X', 't', 'a', 0, 'b'})
+
+	path := filepath.Join(t.TempDir(), "truncated.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSketchMetadata(path); err == nil {
+		t.Error("ReadSketchMetadata aceitou um bloco truncado")
+	}
+}
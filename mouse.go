@@ -0,0 +1,168 @@
+/*
+Projeto: GoSketch - Mouse
+Descrição: Estado do mouse (posição atual e anterior, botões e roda) e callbacks de eventos
+inspirados em p5.js, derivados a cada quadro do fluxo de entrada (input.go)
+*/
+
+package gosketch
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Estado global do mouse
+var (
+	mouseX, mouseY           float64
+	pmouseX, pmouseY         float64
+	mousePressed             bool
+	mouseButton              int = LEFT // Último botão pressionado (LEFT, RIGHT ou CENTER)
+	wheelDeltaX, wheelDeltaY float64
+	mouseInitialized         bool
+
+	mousePressedFn  func()
+	mouseReleasedFn func()
+	mouseClickedFn  func()
+	mouseMovedFn    func()
+	mouseDraggedFn  func()
+	mouseWheelFn    func(dx, dy float64)
+)
+
+// mouseButtons relaciona os botões do Ebiten com as constantes do GoSketch
+var mouseButtons = []struct {
+	button ebiten.MouseButton
+	value  int
+}{
+	{ebiten.MouseButtonLeft, LEFT},
+	{ebiten.MouseButtonRight, RIGHT},
+	{ebiten.MouseButtonMiddle, CENTER},
+}
+
+// MouseX retorna a coordenada horizontal do mouse no canvas
+// Similar à variável mouseX do p5.js/Processing
+func MouseX() float64 { return mouseX }
+
+// MouseY retorna a coordenada vertical do mouse no canvas
+// Similar à variável mouseY do p5.js/Processing
+func MouseY() float64 { return mouseY }
+
+// PMouseX retorna a coordenada horizontal do mouse no quadro anterior
+// Similar à variável pmouseX do p5.js/Processing
+func PMouseX() float64 { return pmouseX }
+
+// PMouseY retorna a coordenada vertical do mouse no quadro anterior
+// Similar à variável pmouseY do p5.js/Processing
+func PMouseY() float64 { return pmouseY }
+
+// MouseIsPressed retorna se algum botão do mouse está pressionado
+// Similar à variável mouseIsPressed do p5.js
+func MouseIsPressed() bool { return mousePressed }
+
+// MouseButton retorna o último botão pressionado: LEFT, RIGHT ou CENTER
+// Similar à variável mouseButton do p5.js/Processing
+func MouseButton() int { return mouseButton }
+
+// MouseWheelDelta retorna o deslocamento da roda do mouse neste quadro
+// Valores positivos de dy indicam rolagem para cima.
+func MouseWheelDelta() (dx, dy float64) { return wheelDeltaX, wheelDeltaY }
+
+// MousePressed registra uma função chamada quando um botão do mouse é pressionado
+// Similar à função mousePressed() do p5.js/Processing
+func MousePressed(f func()) { mousePressedFn = f }
+
+// MouseReleased registra uma função chamada quando um botão do mouse é solto
+// Similar à função mouseReleased() do p5.js/Processing
+func MouseReleased(f func()) { mouseReleasedFn = f }
+
+// MouseClicked registra uma função chamada quando um botão é pressionado e solto
+// Similar à função mouseClicked() do p5.js/Processing
+func MouseClicked(f func()) { mouseClickedFn = f }
+
+// MouseMoved registra uma função chamada quando o mouse se move sem botões pressionados
+// Similar à função mouseMoved() do p5.js/Processing
+func MouseMoved(f func()) { mouseMovedFn = f }
+
+// MouseDragged registra uma função chamada quando o mouse se move com um botão pressionado
+// Similar à função mouseDragged() do p5.js/Processing
+func MouseDragged(f func()) { mouseDraggedFn = f }
+
+// MouseWheel registra uma função chamada quando a roda do mouse é girada,
+// recebendo o deslocamento horizontal e vertical
+// Similar à função mouseWheel() do p5.js
+func MouseWheel(f func(dx, dy float64)) { mouseWheelFn = f }
+
+// cursorPosition retorna a posição do cursor em coordenadas do canvas
+// O Ebiten entrega a posição na tela; a política de escala da janela é desfeita aqui.
+func cursorPosition() (float64, float64) {
+	x, y := ebiten.CursorPosition()
+	return screenToCanvas(float64(x), float64(y))
+}
+
+// updateMouse deriva o estado e os eventos do mouse a partir do quadro de entrada
+// atual e do anterior e dispara os callbacks registrados
+func updateMouse(cur, prev *inputFrame) {
+	if !mouseInitialized {
+		// Evita um movimento falso da origem até o cursor no primeiro quadro
+		prev.MouseX, prev.MouseY = cur.MouseX, cur.MouseY
+		mouseInitialized = true
+	}
+	pmouseX, pmouseY = prev.MouseX, prev.MouseY
+	mouseX, mouseY = cur.MouseX, cur.MouseY
+	wheelDeltaX, wheelDeltaY = cur.WheelX, cur.WheelY
+	mousePressed = len(cur.Buttons) > 0
+
+	var pressed, released bool
+	for _, b := range cur.Buttons {
+		if !containsButton(prev.Buttons, b) {
+			mouseButton = b
+			pressed = true
+		}
+	}
+	for _, b := range prev.Buttons {
+		if !containsButton(cur.Buttons, b) {
+			released = true
+		}
+	}
+
+	if pressed {
+		callHandler("mousePressed", mousePressedFn)
+	}
+	if released {
+		callHandler("mouseReleased", mouseReleasedFn)
+		callHandler("mouseClicked", mouseClickedFn)
+	}
+	if mouseX != pmouseX || mouseY != pmouseY {
+		if mousePressed {
+			callHandler("mouseDragged", mouseDraggedFn)
+		} else {
+			callHandler("mouseMoved", mouseMovedFn)
+		}
+	}
+	if (wheelDeltaX != 0 || wheelDeltaY != 0) && mouseWheelFn != nil {
+		callHandler("mouseWheel", func() { mouseWheelFn(wheelDeltaX, wheelDeltaY) })
+	}
+}
+
+// containsButton retorna se o botão está na lista
+func containsButton(buttons []int, button int) bool {
+	for _, b := range buttons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+// callHandler executa um callback do sketch, reportando pânicos sem interromper o loop
+func callHandler(name string, f func()) {
+	if f == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(newError(ErrPanic, "pânico durante %s: %v", name, r))
+		}
+	}()
+
+	f()
+}
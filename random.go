@@ -0,0 +1,153 @@
+/*
+Projeto: GoSketch - Aleatoriedade e Ruído
+Descrição: Números aleatórios e ruído de Perlin com sementes definíveis, para que um
+sketch possa ser reproduzido a partir das sementes gravadas nos metadados das imagens
+*/
+
+package gosketch
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Parâmetros do ruído, os mesmos do noise() do p5.js
+const (
+	noiseOctaves = 4   // Camadas de ruído somadas
+	noiseFalloff = 0.5 // Peso de cada camada em relação à anterior
+)
+
+// Estado global de aleatoriedade; como o estado de desenho, pertence à goroutine principal
+var (
+	randomSeed   = time.Now().UnixNano()
+	randomSource = newRandomSource(randomSeed)
+	noiseSeed    = randomSeed
+	noisePerm    = newNoisePermutation(noiseSeed)
+)
+
+// newRandomSource cria o gerador de números aleatórios para a semente
+func newRandomSource(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// newNoisePermutation embaralha a tabela de permutação do ruído com a semente
+// A tabela é duplicada para que os índices somados não precisem de módulo.
+func newNoisePermutation(seed int64) *[512]uint8 {
+	r := rand.New(rand.NewPCG(uint64(seed), 1))
+	var perm [512]uint8
+	for i := 0; i < 256; i++ {
+		perm[i] = uint8(i)
+	}
+	r.Shuffle(256, func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+	copy(perm[256:], perm[:256])
+	return &perm
+}
+
+// Random retorna um número aleatório entre min (inclusive) e max (exclusive)
+// A sequência é determinada pela semente de RandomSeed.
+func Random(min, max float64) float64 {
+	return min + randomSource.Float64()*(max-min)
+}
+
+// RandomSeed reinicia o gerador de Random com a semente, repetindo a mesma sequência
+// de números a cada execução
+// Sem RandomSeed, a semente é escolhida a partir do horário no início do programa;
+// GetRandomSeed a retorna e SaveMetadata a grava nas imagens salvas.
+func RandomSeed(seed int64) {
+	randomSeed = seed
+	randomSource = newRandomSource(seed)
+}
+
+// GetRandomSeed retorna a última semente de Random
+func GetRandomSeed() int64 {
+	return randomSeed
+}
+
+// Noise retorna o ruído de Perlin, entre 0 e 1, na coordenada (x, y, z)
+// y e z são opcionais. Coordenadas próximas têm valores próximos; avançar de 0.005 a
+// 0.03 por quadro gera variações suaves. O resultado é determinado pela semente de
+// NoiseSeed.
+func Noise(x float64, yz ...float64) float64 {
+	var y, z float64
+	if len(yz) > 0 {
+		y = yz[0]
+	}
+	if len(yz) > 1 {
+		z = yz[1]
+	}
+
+	sum, amplitude, total := 0.0, 1.0, 0.0
+	for i := 0; i < noiseOctaves; i++ {
+		sum += amplitude * perlin(noisePerm, x, y, z)
+		total += amplitude
+		amplitude *= noiseFalloff
+		x, y, z = x*2, y*2, z*2
+	}
+	// perlin fica entre -1 e 1; a soma ponderada é normalizada para 0 a 1
+	return Constrain((sum/total+1)/2, 0, 1)
+}
+
+// NoiseSeed reinicia o ruído de Noise com a semente
+// Sem NoiseSeed, é usada a semente inicial de Random; GetNoiseSeed a retorna e
+// SaveMetadata a grava nas imagens salvas.
+func NoiseSeed(seed int64) {
+	noiseSeed = seed
+	noisePerm = newNoisePermutation(seed)
+}
+
+// GetNoiseSeed retorna a última semente de Noise
+func GetNoiseSeed() int64 {
+	return noiseSeed
+}
+
+// perlin calcula o ruído de Perlin melhorado (Perlin, 2002) em um ponto
+func perlin(perm *[512]uint8, x, y, z float64) float64 {
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	a := int(perm[xi]) + yi
+	aa, ab := int(perm[a])+zi, int(perm[a+1])+zi
+	b := int(perm[xi+1]) + yi
+	ba, bb := int(perm[b])+zi, int(perm[b+1])+zi
+
+	return Lerp(
+		Lerp(
+			Lerp(grad(perm[aa], x, y, z), grad(perm[ba], x-1, y, z), u),
+			Lerp(grad(perm[ab], x, y-1, z), grad(perm[bb], x-1, y-1, z), u), v),
+		Lerp(
+			Lerp(grad(perm[aa+1], x, y, z-1), grad(perm[ba+1], x-1, y, z-1), u),
+			Lerp(grad(perm[ab+1], x, y-1, z-1), grad(perm[bb+1], x-1, y-1, z-1), u), v),
+		w)
+}
+
+// fade suaviza a interpolação entre os vértices da grade: 6t⁵ - 15t⁴ + 10t³
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// grad retorna o produto escalar entre (x, y, z) e um dos 12 gradientes do cubo
+// escolhido pelo hash
+func grad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	switch {
+	case h < 4:
+		v = y
+	case h == 12 || h == 14:
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
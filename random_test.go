@@ -0,0 +1,89 @@
+package gosketch
+
+import (
+	"math"
+	"testing"
+)
+
+// resetSeeds restaura as sementes de Random e Noise ao final do teste
+func resetSeeds(t *testing.T) {
+	t.Helper()
+	random, noise := GetRandomSeed(), GetNoiseSeed()
+	t.Cleanup(func() {
+		RandomSeed(random)
+		NoiseSeed(noise)
+	})
+}
+
+func TestRandomSeedRepeatsSequence(t *testing.T) {
+	resetSeeds(t)
+
+	RandomSeed(42)
+	first := make([]float64, 10)
+	for i := range first {
+		first[i] = Random(-5, 5)
+		if first[i] < -5 || first[i] >= 5 {
+			t.Fatalf("Random(-5, 5) = %v fora do intervalo", first[i])
+		}
+	}
+
+	RandomSeed(42)
+	for i, want := range first {
+		if got := Random(-5, 5); got != want {
+			t.Fatalf("número %d = %v após reiniciar a semente; esperado %v", i, got, want)
+		}
+	}
+	if GetRandomSeed() != 42 {
+		t.Errorf("GetRandomSeed = %d; esperado 42", GetRandomSeed())
+	}
+}
+
+func TestNoise(t *testing.T) {
+	resetSeeds(t)
+
+	NoiseSeed(7)
+	a := Noise(1.3, 2.7, 0.5)
+	b := Noise(1.31, 2.7, 0.5)
+	if a < 0 || a > 1 {
+		t.Fatalf("Noise = %v fora do intervalo [0, 1]", a)
+	}
+	if math.Abs(a-b) > 0.05 {
+		t.Errorf("Noise variou de %v para %v entre coordenadas próximas", a, b)
+	}
+
+	varies := false
+	for x := 0.0; x < 10; x += 0.37 {
+		if v := Noise(x); v < 0 || v > 1 {
+			t.Fatalf("Noise(%v) = %v fora do intervalo [0, 1]", x, v)
+		} else if math.Abs(v-Noise(0)) > 0.01 {
+			varies = true
+		}
+	}
+	if !varies {
+		t.Error("Noise retornou o mesmo valor em todo o intervalo")
+	}
+
+	NoiseSeed(8)
+	other := Noise(1.3, 2.7, 0.5)
+	NoiseSeed(7)
+	if got := Noise(1.3, 2.7, 0.5); got != a {
+		t.Errorf("Noise = %v após reiniciar a semente; esperado %v", got, a)
+	}
+	if other == a {
+		t.Error("sementes diferentes geraram o mesmo ruído")
+	}
+}
+
+func TestMetadataRecordsSeeds(t *testing.T) {
+	resetSeeds(t)
+	t.Cleanup(NoSaveMetadata)
+
+	RandomSeed(123)
+	NoiseSeed(456)
+	SaveMetadata(SketchMetadata{})
+
+	meta := currentMetadata()
+	if meta.RandomSeed != 123 || meta.NoiseSeed != 456 {
+		t.Errorf("sementes gravadas = %d e %d; esperado 123 e 456", meta.RandomSeed, meta.NoiseSeed)
+	}
+}
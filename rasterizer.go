@@ -0,0 +1,249 @@
+/*
+Projeto: GoSketch - Rasterizador em Blocos
+Descrição: Gravação das formas de um quadro em uma lista de desenho e rasterização
+em memória (sem GPU), dividindo a imagem em blocos desenhados em paralelo. Cada bloco
+desenha todas as formas na ordem em que foram gravadas, recortadas à sua região, de
+modo que o resultado é idêntico ao da rasterização em uma única goroutine.
+*/
+
+package gosketch
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Xistaminose/gosketch/shapes"
+)
+
+// defaultTileSize é o lado, em pixels, dos blocos usados por RasterizeTiled
+const defaultTileSize = 256
+
+// drawCommand é uma forma gravada com o estilo vigente no momento do desenho
+// Comandos sem forma preenchem toda a imagem com a cor de fundo.
+type drawCommand struct {
+	shape         shapes.Shape
+	bounds        image.Rectangle // Região que a forma pode pintar, se bounded
+	bounded       bool
+	background    color.Color
+	fillColor     color.Color
+	strokeColor   color.Color
+	fillEnabled   bool
+	strokeEnabled bool
+	strokeWeight  float64
+}
+
+// DisplayList é a lista das formas desenhadas em um quadro, em ordem, com o estilo
+// (cores de preenchimento e contorno e espessura) de cada uma
+// É criada por RecordDisplayList e rasterizada por Rasterize ou RasterizeTiled.
+type DisplayList struct {
+	Width, Height int
+	commands      []drawCommand
+}
+
+// Len retorna o número de comandos gravados na lista
+func (l *DisplayList) Len() int {
+	return len(l.commands)
+}
+
+// Estado global do rasterizador
+var (
+	displayList   *DisplayList // Lista em gravação na goroutine principal; nil fora de RecordDisplayList
+	rasterWorkers atomic.Int32 // Goroutines de RasterizeTiled; 0 usa runtime.NumCPU()
+)
+
+// RecordDisplayList executa f gravando as formas e os fundos desenhados em uma lista,
+// em vez de desenhá-los no canvas, para rasterizá-los depois em memória
+// Apenas formas (RenderShape e as funções Ellipse, Rectangle, Line etc.) e Background
+// são gravados. Text, TextOnPath, Image, CopyRegion, SetPixel e UpdatePixels não entram
+// na lista: durante a gravação, são ignoradas e reportam ErrUnsupported. Para texto,
+// use TextToShape e desenhe o resultado com RenderShape.
+// Não é necessário um canvas: width e height definem o tamanho da imagem em pixels.
+// Como as funções de desenho, deve ser chamada na goroutine principal ou por QueueDraw
+// (veja concurrency.go); a lista gravada pode ser rasterizada em qualquer goroutine.
+func RecordDisplayList(width, height int, f func()) *DisplayList {
+	if width <= 0 || height <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões inválidas para a lista de desenho: %dx%d - as dimensões devem ser positivas", width, height))
+		return nil
+	}
+	if f == nil {
+		reportError(newError(ErrNilArgument, "função de desenho nula passada para RecordDisplayList"))
+		return nil
+	}
+	if displayList != nil {
+		reportError(newError(ErrInvalidState, "RecordDisplayList não pode ser aninhada"))
+		return nil
+	}
+
+	list := &DisplayList{Width: width, Height: height}
+	displayList = list
+	defer func() { displayList = nil }()
+
+	callHandler("RecordDisplayList", f)
+	return list
+}
+
+// recordShape adiciona a forma à lista em gravação com o estilo atual
+func recordShape(s shapes.Shape) {
+	weight := toPixels(strokeWeight)
+	bounds, bounded := shapes.Bounds(s, weight)
+	displayList.commands = append(displayList.commands, drawCommand{
+		shape:         s,
+		bounds:        bounds,
+		bounded:       bounded,
+		fillColor:     fillColor,
+		strokeColor:   strokeColor,
+		fillEnabled:   fillEnabled,
+		strokeEnabled: strokeEnabled,
+		strokeWeight:  weight,
+	})
+}
+
+// notRecordable informa se uma operação que não entra na lista (texto, imagens e
+// pixels) foi chamada durante RecordDisplayList, reportando ErrUnsupported
+// A operação é ignorada, em vez de desenhar no canvas da tela, para que a diferença
+// entre a imagem rasterizada e o quadro esperado não passe despercebida.
+func notRecordable(name string) bool {
+	if displayList == nil {
+		return false
+	}
+	reportError(newError(ErrUnsupported, "%s não é gravada por RecordDisplayList e foi ignorada - apenas formas e Background entram na lista", name))
+	return true
+}
+
+// recordBackground adiciona à lista em gravação um preenchimento de fundo
+func recordBackground(c color.Color) {
+	displayList.commands = append(displayList.commands, drawCommand{background: c})
+}
+
+// RasterWorkers define quantas goroutines RasterizeTiled usa (padrão: runtime.NumCPU())
+func RasterWorkers(n int) {
+	if n <= 0 {
+		reportError(newError(ErrInvalidArgument, "número de workers inválido: %d - o número deve ser positivo", n))
+		return
+	}
+	rasterWorkers.Store(int32(n))
+}
+
+// Rasterize desenha a lista em uma imagem em memória, em uma única goroutine
+// A imagem começa transparente, como um canvas novo.
+func Rasterize(list *DisplayList) *image.RGBA {
+	if list == nil {
+		reportError(newError(ErrNilArgument, "tentativa de rasterizar uma lista de desenho nula"))
+		return nil
+	}
+	img := image.NewRGBA(image.Rect(0, 0, list.Width, list.Height))
+	list.rasterizeRegion(img, img.Rect)
+	return img
+}
+
+// RasterizeTiled desenha a lista como Rasterize, mas dividindo a imagem em blocos
+// rasterizados em paralelo por RasterWorkers goroutines
+// O resultado é idêntico ao de Rasterize. tileSize opcional define o lado dos blocos
+// em pixels (padrão 256).
+func RasterizeTiled(list *DisplayList, tileSize ...int) *image.RGBA {
+	if list == nil {
+		reportError(newError(ErrNilArgument, "tentativa de rasterizar uma lista de desenho nula"))
+		return nil
+	}
+
+	size := defaultTileSize
+	if len(tileSize) > 0 {
+		if tileSize[0] <= 0 {
+			reportError(newError(ErrInvalidArgument, "tamanho de bloco inválido: %d - o tamanho deve ser positivo", tileSize[0]))
+		} else {
+			size = tileSize[0]
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, list.Width, list.Height))
+	list.rasterizeTiles(img, size)
+	return img
+}
+
+// rasterizeTiles desenha a parte da lista que cabe em img, que pode cobrir apenas uma
+// região da imagem completa, dividindo-a em blocos de size pixels desenhados em paralelo
+func (l *DisplayList) rasterizeTiles(img *image.RGBA, size int) {
+	workers := int(rasterWorkers.Load())
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	tiles := make(chan image.Rectangle)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tile := range tiles {
+				l.rasterizeRegion(img, tile)
+			}
+		}()
+	}
+
+	// Blocos são regiões disjuntas da imagem, então os workers nunca escrevem no mesmo pixel
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y += size {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x += size {
+			tiles <- image.Rect(x, y, x+size, y+size).Intersect(img.Rect)
+		}
+	}
+	close(tiles)
+	wg.Wait()
+}
+
+// rasterizeRegion desenha todos os comandos da lista, em ordem, recortados à região
+// As coordenadas são sempre as da imagem completa; img contém ao menos a região.
+func (l *DisplayList) rasterizeRegion(img *image.RGBA, region image.Rectangle) {
+	target := &rasterCanvas{img: img, clip: region, width: l.Width, height: l.Height}
+
+	for _, cmd := range l.commands {
+		if cmd.shape == nil {
+			draw.Draw(img, region, image.NewUniform(cmd.background), image.Point{}, draw.Src)
+			continue
+		}
+		// Formas que não alcançam a região não precisam ser percorridas
+		if cmd.bounded && !cmd.bounds.Overlaps(region) {
+			continue
+		}
+		l.drawShape(target, cmd)
+	}
+}
+
+// drawShape desenha um comando, reportando pânicos da forma sem interromper os demais
+func (l *DisplayList) drawShape(target *rasterCanvas, cmd drawCommand) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(newError(ErrPanic, "pânico durante renderização: %v", r))
+		}
+	}()
+	cmd.shape.Draw(target, cmd.fillColor, cmd.strokeColor, cmd.fillEnabled, cmd.strokeEnabled, cmd.strokeWeight)
+}
+
+// rasterCanvas é um shapes.Canvas sobre uma imagem em memória que aceita pixels
+// apenas dentro de uma região de recorte
+// A imagem pode cobrir só parte do canvas (uma faixa de RenderTiled); as formas
+// continuam vendo as dimensões do canvas completo.
+type rasterCanvas struct {
+	img           *image.RGBA
+	clip          image.Rectangle
+	width, height int
+}
+
+// Set implementa shapes.Canvas descartando pixels fora da região de recorte
+func (c *rasterCanvas) Set(x, y int, clr color.Color) {
+	if (image.Point{x, y}).In(c.clip) {
+		c.img.Set(x, y, clr)
+	}
+}
+
+// GetWidth implementa shapes.Canvas retornando a largura do canvas completo
+func (c *rasterCanvas) GetWidth() int { return c.width }
+
+// GetHeight implementa shapes.Canvas retornando a altura do canvas completo
+func (c *rasterCanvas) GetHeight() int { return c.height }
+
+// ClipBounds implementa shapes.Clipper, permitindo que as formas pulem os pixels fora do bloco
+func (c *rasterCanvas) ClipBounds() image.Rectangle { return c.clip }
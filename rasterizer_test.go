@@ -0,0 +1,146 @@
+package gosketch
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+
+	"github.com/Xistaminose/gosketch/shapes"
+)
+
+// resetStyle restaura o estilo de desenho e o número de workers ao final do teste
+func resetStyle(t *testing.T) {
+	t.Helper()
+	fill, stroke, weight := fillColor, strokeColor, strokeWeight
+	fillOn, strokeOn := fillEnabled, strokeEnabled
+	workers := rasterWorkers.Load()
+	t.Cleanup(func() {
+		fillColor, strokeColor, strokeWeight = fill, stroke, weight
+		fillEnabled, strokeEnabled = fillOn, strokeOn
+		rasterWorkers.Store(workers)
+	})
+}
+
+// recordTestScene grava formas que cruzam as bordas dos blocos em vários pontos:
+// contornos grossos, um polígono com furo, elipses e formas sem preenchimento ou contorno
+func recordTestScene(t *testing.T) *DisplayList {
+	t.Helper()
+	list := RecordDisplayList(200, 150, func() {
+		BackgroundColor(color.RGBA{20, 30, 40, 255})
+
+		StrokeColor(color.RGBA{250, 200, 0, 255})
+		StrokeWeight(9)
+		Line(3, 5, 197, 143)
+		Line(190, 10, 15, 120)
+		StrokeWeight(1)
+		Line(0, 75.5, 200, 75.5)
+
+		// Quadrado com furo triangular, translúcido sobre as linhas
+		FillColor(color.RGBA{0, 120, 255, 180})
+		StrokeColor(color.RGBA{255, 255, 255, 255})
+		StrokeWeight(4)
+		outer := []shapes.Vertex{{X: 30, Y: 20}, {X: 130, Y: 20}, {X: 130, Y: 120}, {X: 30, Y: 120}}
+		inner := []shapes.Vertex{{X: 60, Y: 40}, {X: 110, Y: 70}, {X: 55, Y: 100}}
+		RenderShape(shapes.NewPolygon(outer, inner))
+
+		FillColor(color.RGBA{200, 40, 90, 255})
+		NoStroke()
+		Ellipse(150, 90, 45, 30)
+		Ellipse(33, 33, 16.5, 16.5)
+
+		NoFill()
+		StrokeColor(color.RGBA{90, 255, 120, 200})
+		StrokeWeight(7.5)
+		Ellipse(100, 75, 70, 50)
+		Rectangle(16.25, 8.75, 120, 90)
+
+		FillColor(color.RGBA{255, 255, 255, 128})
+		StrokeWeight(2)
+		Triangle(180, 5, 199, 149, 140, 140)
+	})
+	if list == nil {
+		t.Fatal("RecordDisplayList retornou nil")
+	}
+	return list
+}
+
+// comparePixels reporta o primeiro pixel diferente entre as duas imagens
+func comparePixels(t *testing.T, want, got *image.RGBA) {
+	t.Helper()
+	if want.Rect != got.Rect {
+		t.Fatalf("dimensões %v; esperado %v", got.Rect, want.Rect)
+	}
+	for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+		for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+			if w, g := want.RGBAAt(x, y), got.RGBAAt(x, y); w != g {
+				t.Fatalf("pixel (%d, %d) = %v; esperado %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestRasterizeTiledMatchesRasterize(t *testing.T) {
+	resetStyle(t)
+	list := recordTestScene(t)
+	want := Rasterize(list)
+
+	// A cena precisa de fato desenhar algo, senão a comparação não prova nada
+	if c := want.RGBAAt(100, 75); c == (color.RGBA{}) {
+		t.Fatal("a rasterização de referência está vazia")
+	}
+
+	for _, size := range []int{1, 7, 16, 33, 64, 256} {
+		for _, workers := range []int{1, 3} {
+			RasterWorkers(workers)
+			got := RasterizeTiled(list, size)
+			t.Run(fmt.Sprintf("blocos=%d/workers=%d", size, workers), func(t *testing.T) {
+				comparePixels(t, want, got)
+			})
+		}
+	}
+}
+
+func TestRecordDisplayListFromWorkerGoroutine(t *testing.T) {
+	resetStyle(t)
+
+	// Uma goroutine de trabalho grava pela fila enquanto a goroutine principal,
+	// aqui a do teste, grava os próprios quadros; nenhuma forma passa de uma lista à outra
+	const workerShapes, frames = 3, 50
+	result := make(chan *DisplayList, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		QueueDraw(func() {
+			result <- RecordDisplayList(100, 100, func() {
+				for i := 0; i < workerShapes; i++ {
+					Ellipse(50, 50, 10, 10)
+				}
+			})
+		})
+	}()
+
+	for frame := 0; frame < frames; frame++ {
+		list := RecordDisplayList(100, 100, func() {
+			Rectangle(10, 10, 20, 20)
+		})
+		if list.Len() != 1 {
+			t.Fatalf("quadro %d gravou %d formas; esperado 1", frame, list.Len())
+		}
+		runDrawQueue()
+	}
+	wg.Wait()
+	runDrawQueue()
+
+	worker := <-result
+	if worker == nil || worker.Len() != workerShapes {
+		t.Fatalf("lista da goroutine de trabalho = %+v; esperado %d formas", worker, workerShapes)
+	}
+
+	// A lista pronta pode ser rasterizada fora da goroutine principal
+	done := make(chan *image.RGBA)
+	go func() { done <- RasterizeTiled(worker, 16) }()
+	comparePixels(t, Rasterize(worker), <-done)
+}
@@ -0,0 +1,586 @@
+/*
+Projeto: GoSketch - Gravação do Canvas
+Descrição: Captura os quadros do canvas a cada draw e os codifica, à medida que são
+capturados, como GIF animado (com paleta compartilhada ou por quadro e dithering
+opcional) ou APNG
+*/
+
+package gosketch
+
+import (
+	"bufio"
+	"bytes"
+	"compress/lzw"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Modos de paleta para gravações em GIF
+const (
+	PalettePerFrame = iota // Cada quadro recebe sua própria paleta de 256 cores
+	PaletteShared          // Uma única paleta, calculada a partir do primeiro quadro
+)
+
+// RecordingOptions configura uma gravação iniciada por StartRecording
+type RecordingOptions struct {
+	Palette   int     // PalettePerFrame (padrão) ou PaletteShared; usado apenas em GIF
+	Dither    bool    // Aplica dithering Floyd-Steinberg na quantização do GIF
+	Loops     int     // Número de repetições da animação (0 = infinito)
+	MaxFrames int     // Encerra a gravação automaticamente após n quadros (0 = sem limite)
+	FrameRate float64 // Quadros por segundo da saída (0 = usa o valor de FrameRate)
+}
+
+// recording guarda o estado de uma gravação em andamento
+// Os quadros são codificados à medida que são capturados em um arquivo temporário,
+// renomeado para filename ao final, de modo que a memória usada não cresce com a duração.
+type recording struct {
+	filename  string
+	format    string // "gif" ou "apng"
+	opts      RecordingOptions
+	file      *os.File // Arquivo temporário no diretório de filename
+	out       *bufio.Writer
+	frames    int           // Quadros já codificados
+	palette   color.Palette // Paleta compartilhada, calculada no primeiro quadro
+	sequence  uint32        // Próximo número de sequência dos blocos do APNG
+	interval  float64       // Duração de cada quadro capturado em milissegundos
+	skip      int           // Captura um a cada skip quadros desenhados
+	drawn     int           // Quadros desenhados desde o início da gravação
+	elapsed   float64       // Tempo acumulado para arredondar os atrasos sem deriva
+	elapsedMS int           // Soma dos atrasos já gravados, em milissegundos
+}
+
+// Estado global da gravação
+var activeRecording *recording
+
+// gifMinFrameDelay é o menor atraso respeitado pelos navegadores em GIFs (em milissegundos)
+const gifMinFrameDelay = 20
+
+// apngACTLOffset é a posição do bloco acTL no arquivo: após a assinatura e o bloco IHDR
+// O número de quadros só é conhecido no fim da gravação e é escrito nessa posição.
+const apngACTLOffset = len(pngSignature) + 12 + 13
+
+// StartRecording começa a capturar os quadros do canvas a cada execução de draw
+// O formato é escolhido pela extensão: ".gif" para GIF animado, ".png" ou ".apng" para APNG.
+// Os quadros são gravados em um arquivo temporário no mesmo diretório; filename só é
+// substituído quando StopRecording é chamada.
+func StartRecording(filename string, opts ...RecordingOptions) error {
+	if activeRecording != nil {
+		return newError(ErrRecording, "já existe uma gravação em andamento para '%s'", activeRecording.filename)
+	}
+
+	var format string
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gif":
+		format = "gif"
+	case ".png", ".apng":
+		format = "apng"
+	default:
+		return newError(ErrUnsupported, "formato de gravação não suportado para '%s' - use .gif, .png ou .apng", filename)
+	}
+
+	rec := &recording{filename: filename, format: format, skip: 1}
+	if len(opts) > 0 {
+		rec.opts = opts[0]
+	}
+	if rec.opts.Palette != PalettePerFrame && rec.opts.Palette != PaletteShared {
+		return newError(ErrInvalidArgument, "modo de paleta inválido: %d - use PalettePerFrame ou PaletteShared", rec.opts.Palette)
+	}
+
+	fps := rec.opts.FrameRate
+	if fps <= 0 {
+		fps = float64(targetFPS)
+	}
+	if fps <= 0 {
+		fps = 60
+	}
+	rec.interval = 1000 / fps
+
+	// GIFs não representam atrasos menores que 20ms, então quadros são descartados
+	// para manter a velocidade correta da animação
+	if format == "gif" && rec.interval < gifMinFrameDelay {
+		rec.skip = int(math.Ceil(gifMinFrameDelay / rec.interval))
+		rec.interval *= float64(rec.skip)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return newError(ErrWriteFailed, "erro ao criar arquivo '%s': %w", filename, err)
+	}
+	rec.file = file
+	rec.out = bufio.NewWriter(file)
+
+	activeRecording = rec
+	return nil
+}
+
+// IsRecording retorna se há uma gravação em andamento
+func IsRecording() bool {
+	return activeRecording != nil
+}
+
+// StopRecording encerra a gravação atual e escreve o arquivo
+func StopRecording() error {
+	rec := activeRecording
+	if rec == nil {
+		return newError(ErrRecording, "nenhuma gravação em andamento")
+	}
+	activeRecording = nil
+
+	if rec.frames == 0 {
+		rec.discard()
+		return newError(ErrRecording, "gravação '%s' não capturou nenhum quadro", rec.filename)
+	}
+
+	if err := rec.finish(); err != nil {
+		rec.discard()
+		return newError(ErrWriteFailed, "erro ao codificar gravação '%s': %w", rec.filename, err)
+	}
+	if err := os.Rename(rec.file.Name(), rec.filename); err != nil {
+		os.Remove(rec.file.Name())
+		return newError(ErrWriteFailed, "erro ao criar arquivo '%s': %w", rec.filename, err)
+	}
+	return nil
+}
+
+// captureRecordingFrame é chamada após cada execução de draw
+func captureRecordingFrame() {
+	rec := activeRecording
+	if rec == nil || canvas == nil {
+		return
+	}
+
+	rec.drawn++
+	if (rec.drawn-1)%rec.skip != 0 {
+		return
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, canvas.GetWidth(), canvas.GetHeight()))
+	canvas.img.ReadPixels(frame.Pix)
+
+	// Arredonda a partir do tempo acumulado para que a soma dos atrasos não derive
+	before := int(math.Round(rec.elapsed))
+	rec.elapsed += rec.interval
+	delay := int(math.Round(rec.elapsed)) - before
+
+	if err := rec.writeFrame(frame, delay); err != nil {
+		activeRecording = nil
+		rec.discard()
+		reportError(newError(ErrWriteFailed, "erro ao codificar gravação '%s': %w", rec.filename, err))
+		return
+	}
+
+	if rec.opts.MaxFrames > 0 && rec.frames >= rec.opts.MaxFrames {
+		if err := StopRecording(); err != nil {
+			reportError(err)
+		}
+	}
+}
+
+// writeFrame codifica um quadro no arquivo temporário; o primeiro quadro define
+// as dimensões e escreve o cabeçalho
+func (rec *recording) writeFrame(frame *image.RGBA, delay int) error {
+	if rec.frames == 0 {
+		if rec.format == "gif" {
+			rec.writeGIFHeader(frame.Rect.Dx(), frame.Rect.Dy())
+		} else {
+			rec.writeAPNGHeader(frame.Rect.Dx(), frame.Rect.Dy())
+		}
+	}
+
+	var err error
+	if rec.format == "gif" {
+		err = rec.writeGIFFrame(frame, delay)
+	} else {
+		err = rec.writeAPNGFrame(frame, delay)
+	}
+	if err != nil {
+		return err
+	}
+	rec.frames++
+
+	// Cada quadro vai para o disco antes do próximo, que também revela erros de escrita
+	return rec.out.Flush()
+}
+
+// finish escreve o final do arquivo e o fecha
+func (rec *recording) finish() error {
+	if rec.format == "gif" {
+		rec.out.WriteByte(0x3B) // Fim do GIF
+	} else {
+		writePNGChunk(rec.out, "IEND", nil)
+	}
+	if err := rec.out.Flush(); err != nil {
+		return err
+	}
+
+	if rec.format == "apng" {
+		var actl bytes.Buffer
+		writePNGChunk(&actl, "acTL", rec.animationControl())
+		if _, err := rec.file.WriteAt(actl.Bytes(), int64(apngACTLOffset)); err != nil {
+			return err
+		}
+	}
+	return rec.file.Close()
+}
+
+// discard fecha e remove o arquivo temporário de uma gravação descartada
+func (rec *recording) discard() {
+	rec.file.Close()
+	os.Remove(rec.file.Name())
+}
+
+// writeGIFHeader escreve o cabeçalho do GIF, sem paleta global, e a extensão de repetição
+func (rec *recording) writeGIFHeader(width, height int) {
+	header := make([]byte, 13)
+	copy(header, "GIF89a")
+	binary.LittleEndian.PutUint16(header[6:8], uint16(width))
+	binary.LittleEndian.PutUint16(header[8:10], uint16(height))
+	rec.out.Write(header)
+
+	// Loops = 1 toca a animação uma vez, sem a extensão; nas demais, a contagem do GIF
+	// é o número de repetições além da primeira (0 = infinito)
+	if rec.opts.Loops != 1 {
+		loops := max(rec.opts.Loops-1, 0)
+		rec.out.Write([]byte{0x21, 0xFF, 0x0B})
+		rec.out.WriteString("NETSCAPE2.0")
+		rec.out.Write([]byte{0x03, 0x01, byte(loops), byte(loops >> 8), 0x00})
+	}
+}
+
+// writeGIFFrame quantiza um quadro e o escreve com sua paleta local
+// Os atrasos do GIF são em centésimos de segundo, arredondados a partir do tempo acumulado.
+func (rec *recording) writeGIFFrame(frame *image.RGBA, delay int) error {
+	palette := rec.palette
+	if palette == nil {
+		palette = quantizePalette([]*image.RGBA{frame}, 256)
+		if rec.opts.Palette == PaletteShared {
+			rec.palette = palette
+		}
+	}
+
+	paletted := image.NewPaletted(frame.Bounds(), palette)
+	if rec.opts.Dither {
+		draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+	} else {
+		draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+	}
+
+	before := (rec.elapsedMS + 5) / 10
+	rec.elapsedMS += delay
+	centiseconds := (rec.elapsedMS+5)/10 - before
+
+	// Extensão de controle gráfico: atraso e índice 0 transparente
+	rec.out.Write([]byte{0x21, 0xF9, 0x04, 0x01, byte(centiseconds), byte(centiseconds >> 8), 0x00, 0x00})
+
+	// A tabela de cores tem 2^bits entradas, completadas com preto
+	bits := 1
+	for 1<<bits < len(palette) {
+		bits++
+	}
+
+	descriptor := make([]byte, 10)
+	descriptor[0] = 0x2C
+	binary.LittleEndian.PutUint16(descriptor[5:7], uint16(frame.Rect.Dx()))
+	binary.LittleEndian.PutUint16(descriptor[7:9], uint16(frame.Rect.Dy()))
+	descriptor[9] = 0x80 | byte(bits-1) // Paleta local
+	rec.out.Write(descriptor)
+
+	table := make([]byte, 3<<bits)
+	for i, c := range palette {
+		r, g, b, _ := c.RGBA()
+		table[3*i], table[3*i+1], table[3*i+2] = byte(r>>8), byte(g>>8), byte(b>>8)
+	}
+	rec.out.Write(table)
+
+	litWidth := max(bits, 2)
+	rec.out.WriteByte(byte(litWidth))
+	blocks := &gifBlockWriter{w: rec.out}
+	lw := lzw.NewWriter(blocks, lzw.LSB, litWidth)
+	if _, err := lw.Write(paletted.Pix); err != nil {
+		return err
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	return blocks.Close()
+}
+
+// gifBlockWriter divide os dados comprimidos de um quadro GIF em sub-blocos de até 255 bytes
+type gifBlockWriter struct {
+	w   io.Writer
+	buf [256]byte // buf[0] guarda o tamanho do sub-bloco
+	n   int
+}
+
+// Write implementa io.Writer
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	total := len(p)
+	for len(p) > 0 {
+		n := copy(b.buf[1+b.n:], p)
+		b.n += n
+		p = p[n:]
+		if b.n == 255 {
+			if err := b.flush(); err != nil {
+				return total - len(p), err
+			}
+		}
+	}
+	return total, nil
+}
+
+// flush escreve o sub-bloco pendente
+func (b *gifBlockWriter) flush() error {
+	if b.n == 0 {
+		return nil
+	}
+	b.buf[0] = byte(b.n)
+	_, err := b.w.Write(b.buf[:1+b.n])
+	b.n = 0
+	return err
+}
+
+// Close escreve o último sub-bloco e o terminador
+func (b *gifBlockWriter) Close() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+	_, err := b.w.Write([]byte{0x00})
+	return err
+}
+
+// writeAPNGHeader escreve o cabeçalho de um PNG animado RGBA de 8 bits
+// O bloco acTL é reservado aqui e reescrito por finish com o número de quadros.
+func (rec *recording) writeAPNGHeader(width, height int) {
+	rec.out.WriteString(pngSignature)
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(height))
+	ihdr[8] = 8 // Bits por canal
+	ihdr[9] = 6 // Tipo de cor: RGBA
+	writePNGChunk(rec.out, "IHDR", ihdr)
+	writePNGChunk(rec.out, "acTL", rec.animationControl())
+}
+
+// animationControl retorna os dados do bloco acTL: número de quadros e de repetições
+func (rec *recording) animationControl() []byte {
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:4], uint32(rec.frames))
+	binary.BigEndian.PutUint32(actl[4:8], uint32(rec.opts.Loops))
+	return actl
+}
+
+// writeAPNGFrame escreve o controle e os dados comprimidos de um quadro do APNG
+func (rec *recording) writeAPNGFrame(frame *image.RGBA, delay int) error {
+	bounds := frame.Bounds()
+
+	fctl := make([]byte, 26)
+	binary.BigEndian.PutUint32(fctl[0:4], rec.sequence)
+	binary.BigEndian.PutUint32(fctl[4:8], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(fctl[8:12], uint32(bounds.Dy()))
+	binary.BigEndian.PutUint16(fctl[20:22], uint16(delay))
+	binary.BigEndian.PutUint16(fctl[22:24], 1000) // Atraso em milissegundos
+	writePNGChunk(rec.out, "fcTL", fctl)
+	rec.sequence++
+
+	data, err := compressPNGFrame(frame)
+	if err != nil {
+		return err
+	}
+
+	if rec.frames == 0 {
+		writePNGChunk(rec.out, "IDAT", data)
+	} else {
+		fdat := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, rec.sequence)
+		writePNGChunk(rec.out, "fdAT", append(fdat, data...))
+		rec.sequence++
+	}
+	return nil
+}
+
+// compressPNGFrame gera os dados comprimidos (IDAT) de um quadro RGBA não pré-multiplicado
+// A primeira linha usa o filtro None e as demais o filtro Up, que funciona bem
+// para o conteúdo típico de sketches
+func compressPNGFrame(frame *image.RGBA) ([]byte, error) {
+	var out bytes.Buffer
+	enc := newPNGRowEncoder(&out, frame.Bounds().Dx())
+	if err := enc.writeRows(frame); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// pngRowEncoder filtra e comprime linhas RGBA nos dados IDAT de um PNG
+// A imagem pode ser enviada em faixas sucessivas (veja RenderTiled), sem que
+// precise estar inteira na memória.
+type pngRowEncoder struct {
+	zw        *zlib.Writer
+	prev, row []byte
+	line      []byte
+	started   bool
+}
+
+// newPNGRowEncoder cria um codificador de linhas com a largura em pixels informada
+func newPNGRowEncoder(w io.Writer, width int) *pngRowEncoder {
+	rowSize := width * 4
+	return &pngRowEncoder{
+		zw:   zlib.NewWriter(w),
+		prev: make([]byte, rowSize),
+		row:  make([]byte, rowSize),
+		line: make([]byte, 1+rowSize),
+	}
+}
+
+// writeRows codifica as linhas de img, de cima para baixo, após as já escritas
+func (e *pngRowEncoder) writeRows(img *image.RGBA) error {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.RGBAAt(x, y)).(color.NRGBA)
+			i := (x - bounds.Min.X) * 4
+			e.row[i], e.row[i+1], e.row[i+2], e.row[i+3] = c.R, c.G, c.B, c.A
+		}
+
+		if !e.started {
+			e.line[0] = 0 // Filtro None
+			copy(e.line[1:], e.row)
+			e.started = true
+		} else {
+			e.line[0] = 2 // Filtro Up
+			for i := range e.row {
+				e.line[1+i] = e.row[i] - e.prev[i]
+			}
+		}
+
+		if _, err := e.zw.Write(e.line); err != nil {
+			return err
+		}
+		e.prev, e.row = e.row, e.prev
+	}
+	return nil
+}
+
+// Close termina o fluxo comprimido
+func (e *pngRowEncoder) Close() error {
+	return e.zw.Close()
+}
+
+// colorBox é um grupo de cores usado pelo algoritmo median cut
+type colorBox []color.RGBA
+
+// quantizePalette calcula uma paleta de até n cores para os quadros usando median cut
+// O índice 0 é reservado para a cor transparente, usada por pixels com alfa baixo.
+func quantizePalette(frames []*image.RGBA, n int) color.Palette {
+	// Amostra no máximo ~256k pixels para manter a quantização rápida
+	total := 0
+	for _, frame := range frames {
+		total += len(frame.Pix) / 4
+	}
+	step := total/(1<<18) + 1
+
+	var samples colorBox
+	index := 0
+	for _, frame := range frames {
+		for i := 0; i < len(frame.Pix); i += 4 {
+			index++
+			if index%step != 0 || frame.Pix[i+3] < 128 {
+				continue
+			}
+			samples = append(samples, color.RGBA{frame.Pix[i], frame.Pix[i+1], frame.Pix[i+2], 255})
+		}
+	}
+
+	palette := color.Palette{color.Transparent}
+	if len(samples) == 0 {
+		return palette
+	}
+
+	boxes := []colorBox{samples}
+	for len(boxes) < n-1 {
+		// Divide a caixa com a maior amplitude em algum canal
+		widest, channel, span := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			c, s := box.widestChannel()
+			if s > span {
+				widest, channel, span = i, c, s
+			}
+		}
+		if widest < 0 {
+			break
+		}
+
+		box := boxes[widest]
+		sort.Slice(box, func(a, b int) bool {
+			return channelValue(box[a], channel) < channelValue(box[b], channel)
+		})
+		mid := len(box) / 2
+		boxes[widest] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	for _, box := range boxes {
+		palette = append(palette, box.average())
+	}
+	return palette
+}
+
+// widestChannel retorna o canal (0=R, 1=G, 2=B) com maior amplitude e essa amplitude
+func (b colorBox) widestChannel() (int, int) {
+	bestChannel, bestSpan := 0, -1
+	for channel := 0; channel < 3; channel++ {
+		lo, hi := 255, 0
+		for _, c := range b {
+			v := int(channelValue(c, channel))
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if hi-lo > bestSpan {
+			bestChannel, bestSpan = channel, hi-lo
+		}
+	}
+	return bestChannel, bestSpan
+}
+
+// average retorna a cor média da caixa
+func (b colorBox) average() color.RGBA {
+	var r, g, bl int
+	for _, c := range b {
+		r += int(c.R)
+		g += int(c.G)
+		bl += int(c.B)
+	}
+	n := len(b)
+	return color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 255}
+}
+
+// channelValue retorna o valor de um canal de cor
+func channelValue(c color.RGBA, channel int) uint8 {
+	switch channel {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	default:
+		return c.B
+	}
+}
@@ -36,4 +36,9 @@ func CreatePoint(x, y float64) *PointShape {
 // CreateTriangle creates a new triangle shape without drawing it
 func CreateTriangle(x1, y1, x2, y2, x3, y3 float64) *TriangleShape {
 	return NewTriangle(x1, y1, x2, y2, x3, y3)
-} 
\ No newline at end of file
+}
+
+// CreatePolygon creates a new polygon shape without drawing it
+func CreatePolygon(contours ...[]Vertex) *PolygonShape {
+	return NewPolygon(contours...)
+}
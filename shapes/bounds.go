@@ -0,0 +1,90 @@
+package shapes
+
+import (
+	"image"
+	"math"
+)
+
+// Bounded é implementada por formas que conhecem a região que podem pintar,
+// usada para pular formas fora de um bloco ao rasterizar em paralelo
+type Bounded interface {
+	// Bounds retorna um retângulo que contém todos os pixels que a forma pode
+	// pintar com a espessura de contorno informada
+	Bounds(strokeWeight float64) image.Rectangle
+}
+
+// Bounds retorna a região que a forma pode pintar, se ela implementar Bounded
+// O segundo valor indica se a região é conhecida.
+func Bounds(s Shape, strokeWeight float64) (image.Rectangle, bool) {
+	b, ok := s.(Bounded)
+	if !ok {
+		return image.Rectangle{}, false
+	}
+	return b.Bounds(strokeWeight), true
+}
+
+// boundsOf retorna a região que contém as coordenadas com uma margem para o contorno
+// A margem é generosa: a região só precisa conter os pixels pintados, não ser justa.
+func boundsOf(minX, minY, maxX, maxY, strokeWeight float64) image.Rectangle {
+	pad := math.Max(strokeWeight/2, 1) + 2
+	return image.Rect(
+		int(math.Floor(minX-pad)), int(math.Floor(minY-pad)),
+		int(math.Ceil(maxX+pad))+1, int(math.Ceil(maxY+pad))+1,
+	)
+}
+
+// lineBounds retorna a região que o contorno de uma linha pode pintar
+// O traçado de Bresenham pode passar do destino quando os extremos não são inteiros,
+// até o limite de segurança de LineShape.Stroke (o dobro da distância a partir de X1, Y1).
+func lineBounds(x1, y1, x2, y2, strokeWeight float64) image.Rectangle {
+	reachX := 2*math.Abs(x2-x1) + 1
+	reachY := 2*math.Abs(y2-y1) + 1
+	return boundsOf(x1-reachX, y1-reachY, x1+reachX, y1+reachY, strokeWeight)
+}
+
+// contourBounds retorna a região que um contorno fechado pode pintar
+func contourBounds(vertices []Vertex, strokeWeight float64) image.Rectangle {
+	var bounds image.Rectangle
+	for i := range vertices {
+		a, b := vertices[i], vertices[(i+1)%len(vertices)]
+		bounds = bounds.Union(boundsOf(a.X, a.Y, a.X, a.Y, strokeWeight))
+		bounds = bounds.Union(lineBounds(a.X, a.Y, b.X, b.Y, strokeWeight))
+	}
+	return bounds
+}
+
+// Bounds implementa Bounded para elipses
+func (e *EllipseShape) Bounds(strokeWeight float64) image.Rectangle {
+	rx, ry := math.Abs(e.Rx), math.Abs(e.Ry)
+	return boundsOf(e.X-rx, e.Y-ry, e.X+rx, e.Y+ry, strokeWeight)
+}
+
+// Bounds implementa Bounded para retângulos
+// Com largura ou altura negativas o contorno é desenhado para fora, daí a margem dobrada.
+func (r *RectangleShape) Bounds(strokeWeight float64) image.Rectangle {
+	return boundsOf(math.Min(r.X, r.X+r.W), math.Min(r.Y, r.Y+r.H), math.Max(r.X, r.X+r.W), math.Max(r.Y, r.Y+r.H), 2*strokeWeight)
+}
+
+// Bounds implementa Bounded para linhas
+func (l *LineShape) Bounds(strokeWeight float64) image.Rectangle {
+	return lineBounds(l.X1, l.Y1, l.X2, l.Y2, strokeWeight)
+}
+
+// Bounds implementa Bounded para pontos
+func (p *PointShape) Bounds(strokeWeight float64) image.Rectangle {
+	return boundsOf(p.X, p.Y, p.X, p.Y, strokeWeight)
+}
+
+// Bounds implementa Bounded para triângulos
+func (t *TriangleShape) Bounds(strokeWeight float64) image.Rectangle {
+	return contourBounds([]Vertex{{t.X1, t.Y1}, {t.X2, t.Y2}, {t.X3, t.Y3}}, strokeWeight)
+}
+
+// Bounds implementa Bounded para polígonos
+func (p *PolygonShape) Bounds(strokeWeight float64) image.Rectangle {
+	var bounds image.Rectangle
+	for _, contour := range p.Contours {
+		bounds = bounds.Union(contourBounds(contour, strokeWeight))
+	}
+	return bounds
+}
@@ -0,0 +1,19 @@
+package shapes
+
+import "image"
+
+// Clipper é implementada por canvas que aceitam pixels apenas em uma região,
+// como os blocos de um rasterizador em paralelo. As formas usam a região para
+// pular pixels que seriam descartados por Set, sem alterar o resultado.
+type Clipper interface {
+	ClipBounds() image.Rectangle
+}
+
+// clipBounds retorna a região do canvas que pode receber pixels
+func clipBounds(canvas Canvas) image.Rectangle {
+	bounds := image.Rect(0, 0, canvas.GetWidth(), canvas.GetHeight())
+	if c, ok := canvas.(Clipper); ok {
+		return c.ClipBounds().Intersect(bounds)
+	}
+	return bounds
+}
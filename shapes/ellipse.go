@@ -16,10 +16,15 @@ func (e *EllipseShape) Fill(canvas Canvas, fillColor color.Color, fillEnabled bo
 	if !fillEnabled {
 		return
 	}
-	for dx := -int(e.Rx); dx <= int(e.Rx); dx++ {
-		for dy := -int(e.Ry); dy <= int(e.Ry); dy++ {
+	// Percorre apenas a parte da elipse dentro da região de recorte
+	clip := clipBounds(canvas)
+	cx, cy := int(e.X), int(e.Y)
+	startX, endX := max(-int(e.Rx), clip.Min.X-cx), min(int(e.Rx), clip.Max.X-1-cx)
+	startY, endY := max(-int(e.Ry), clip.Min.Y-cy), min(int(e.Ry), clip.Max.Y-1-cy)
+	for dx := startX; dx <= endX; dx++ {
+		for dy := startY; dy <= endY; dy++ {
 			if float64(dx*dx)/(e.Rx*e.Rx)+float64(dy*dy)/(e.Ry*e.Ry) <= 1 {
-				canvas.Set(int(e.X)+dx, int(e.Y)+dy, fillColor)
+				canvas.Set(cx+dx, cy+dy, fillColor)
 			}
 		}
 	}
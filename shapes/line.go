@@ -85,6 +85,12 @@ func drawThickPoint(canvas Canvas, x, y int, color color.Color, thickness float6
 	if radius < 1 {
 		radius = 1
 	}
+
+	// Ignora pontos inteiramente fora da região de recorte
+	clip := clipBounds(canvas)
+	if x+radius < clip.Min.X || x-radius >= clip.Max.X || y+radius < clip.Min.Y || y-radius >= clip.Max.Y {
+		return
+	}
 	
 	for dx := -radius; dx <= radius; dx++ {
 		for dy := -radius; dy <= radius; dy++ {
@@ -0,0 +1,119 @@
+package shapes
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Vertex é um vértice de um contorno
+type Vertex struct {
+	X, Y float64
+}
+
+// PolygonShape implementa Shape para polígonos com um ou mais contornos fechados
+// Contornos internos com orientação oposta formam buracos (regra de preenchimento nonzero),
+// como nos contornos de glifos de fontes.
+type PolygonShape struct {
+	BaseShape
+	Contours [][]Vertex
+}
+
+// Fill preenche o interior do polígono usando a regra nonzero, se fillEnabled
+func (p *PolygonShape) Fill(canvas Canvas, fillColor color.Color, fillEnabled bool) {
+	if !fillEnabled {
+		return
+	}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, contour := range p.Contours {
+		for _, v := range contour {
+			minY = math.Min(minY, v.Y)
+			maxY = math.Max(maxY, v.Y)
+		}
+	}
+	if math.IsInf(minY, 1) {
+		return
+	}
+
+	// Limita o escaneamento ao tamanho do canvas
+	startY := int(math.Max(0, math.Floor(minY)))
+	endY := int(math.Min(float64(canvas.GetHeight()-1), math.Ceil(maxY)))
+	canvasWidth := canvas.GetWidth()
+
+	// E à região de recorte, quando o canvas tiver uma
+	clip := clipBounds(canvas)
+	startY, endY = max(startY, clip.Min.Y), min(endY, clip.Max.Y-1)
+
+	type crossing struct {
+		x       float64
+		winding int
+	}
+	var crossings []crossing
+
+	for y := startY; y <= endY; y++ {
+		// Amostra no centro do pixel
+		sy := float64(y) + 0.5
+
+		crossings = crossings[:0]
+		for _, contour := range p.Contours {
+			for i := range contour {
+				a, b := contour[i], contour[(i+1)%len(contour)]
+				if a.Y == b.Y {
+					continue
+				}
+				winding := 1
+				if a.Y > b.Y {
+					a, b = b, a
+					winding = -1
+				}
+				if sy < a.Y || sy >= b.Y {
+					continue
+				}
+				x := a.X + (sy-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+				crossings = append(crossings, crossing{x: x, winding: winding})
+			}
+		}
+
+		sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+		// Preenche os trechos onde o número de voltas é diferente de zero
+		winding := 0
+		for i := 0; i < len(crossings)-1; i++ {
+			winding += crossings[i].winding
+			if winding == 0 {
+				continue
+			}
+			startX := int(math.Max(0, math.Ceil(crossings[i].x-0.5)))
+			endX := int(math.Min(float64(canvasWidth-1), math.Ceil(crossings[i+1].x-0.5)-1))
+			startX, endX = max(startX, clip.Min.X), min(endX, clip.Max.X-1)
+			for x := startX; x <= endX; x++ {
+				canvas.Set(x, y, fillColor)
+			}
+		}
+	}
+}
+
+// Stroke desenha cada contorno como uma linha fechada conforme strokeWeight
+func (p *PolygonShape) Stroke(canvas Canvas, strokeColor color.Color, strokeEnabled bool, strokeWeight float64) {
+	if !strokeEnabled {
+		return
+	}
+	for _, contour := range p.Contours {
+		for i := range contour {
+			a, b := contour[i], contour[(i+1)%len(contour)]
+			line := LineShape{X1: a.X, Y1: a.Y, X2: b.X, Y2: b.Y}
+			line.Stroke(canvas, strokeColor, strokeEnabled, strokeWeight)
+		}
+	}
+}
+
+// Draw executa fill e stroke no polígono
+func (p *PolygonShape) Draw(canvas Canvas, fillColor, strokeColor color.Color, fillEnabled, strokeEnabled bool, strokeWeight float64) {
+	p.BaseShape.Draw(canvas, p, fillColor, strokeColor, fillEnabled, strokeEnabled, strokeWeight)
+}
+
+// NewPolygon cria uma nova forma de polígono a partir de um ou mais contornos
+func NewPolygon(contours ...[]Vertex) *PolygonShape {
+	return &PolygonShape{Contours: contours}
+}
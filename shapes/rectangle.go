@@ -15,9 +15,14 @@ func (r *RectangleShape) Fill(canvas Canvas, fillColor color.Color, fillEnabled
 	if !fillEnabled {
 		return
 	}
-	for dx := 0; dx < int(r.W); dx++ {
-		for dy := 0; dy < int(r.H); dy++ {
-			canvas.Set(int(r.X)+dx, int(r.Y)+dy, fillColor)
+	// Percorre apenas a parte do retângulo dentro da região de recorte
+	clip := clipBounds(canvas)
+	x0, y0 := int(r.X), int(r.Y)
+	startX, endX := max(0, clip.Min.X-x0), min(int(r.W), clip.Max.X-x0)
+	startY, endY := max(0, clip.Min.Y-y0), min(int(r.H), clip.Max.Y-y0)
+	for dx := startX; dx < endX; dx++ {
+		for dy := startY; dy < endY; dy++ {
+			canvas.Set(x0+dx, y0+dy, fillColor)
 		}
 	}
 }
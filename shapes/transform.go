@@ -0,0 +1,61 @@
+package shapes
+
+// Transformable é implementada por formas que sabem produzir uma cópia escalada e
+// deslocada de si mesmas, usada para desenhar em canvas com densidade de pixels
+// diferente de 1 sem perder nitidez
+type Transformable interface {
+	// Transformed retorna uma cópia da forma com cada coordenada mapeada para
+	// (x*scale + dx, y*scale + dy); a forma original não é alterada
+	Transformed(scale, dx, dy float64) Shape
+}
+
+// Transform aplica a transformação à forma se ela implementar Transformable
+// O segundo valor indica se a transformação foi aplicada.
+func Transform(s Shape, scale, dx, dy float64) (Shape, bool) {
+	t, ok := s.(Transformable)
+	if !ok {
+		return s, false
+	}
+	return t.Transformed(scale, dx, dy), true
+}
+
+// Transformed implementa Transformable para elipses
+func (e *EllipseShape) Transformed(scale, dx, dy float64) Shape {
+	return NewEllipse(e.X*scale+dx, e.Y*scale+dy, e.Rx*scale, e.Ry*scale)
+}
+
+// Transformed implementa Transformable para retângulos
+func (r *RectangleShape) Transformed(scale, dx, dy float64) Shape {
+	return NewRectangle(r.X*scale+dx, r.Y*scale+dy, r.W*scale, r.H*scale)
+}
+
+// Transformed implementa Transformable para linhas
+func (l *LineShape) Transformed(scale, dx, dy float64) Shape {
+	return NewLine(l.X1*scale+dx, l.Y1*scale+dy, l.X2*scale+dx, l.Y2*scale+dy)
+}
+
+// Transformed implementa Transformable para pontos
+func (p *PointShape) Transformed(scale, dx, dy float64) Shape {
+	return NewPoint(p.X*scale+dx, p.Y*scale+dy)
+}
+
+// Transformed implementa Transformable para triângulos
+func (t *TriangleShape) Transformed(scale, dx, dy float64) Shape {
+	return NewTriangle(
+		t.X1*scale+dx, t.Y1*scale+dy,
+		t.X2*scale+dx, t.Y2*scale+dy,
+		t.X3*scale+dx, t.Y3*scale+dy,
+	)
+}
+
+// Transformed implementa Transformable para polígonos
+func (p *PolygonShape) Transformed(scale, dx, dy float64) Shape {
+	contours := make([][]Vertex, len(p.Contours))
+	for i, contour := range p.Contours {
+		contours[i] = make([]Vertex, len(contour))
+		for j, v := range contour {
+			contours[i][j] = Vertex{X: v.X*scale + dx, Y: v.Y*scale + dy}
+		}
+	}
+	return NewPolygon(contours...)
+}
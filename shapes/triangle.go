@@ -37,6 +37,11 @@ func (t *TriangleShape) Fill(canvas Canvas, fillColor color.Color, fillEnabled b
 	endX := int(math.Min(float64(canvasWidth-1), maxX))
 	startY := int(math.Max(0, minY))
 	endY := int(math.Min(float64(canvasHeight-1), maxY))
+
+	// E à região de recorte, quando o canvas tiver uma
+	clip := clipBounds(canvas)
+	startX, endX = max(startX, clip.Min.X), min(endX, clip.Max.X-1)
+	startY, endY = max(startY, clip.Min.Y), min(endY, clip.Max.Y-1)
 	
 	// Algoritmo de preenchimento por escaneamento
 	for y := startY; y <= endY; y++ {
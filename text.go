@@ -0,0 +1,364 @@
+/*
+Projeto: GoSketch - Texto
+Descrição: Desenho e layout de texto inspirados em p5.js: alinhamento, entrelinha,
+múltiplas linhas, quebra automática em caixa, kerning e métricas da fonte
+*/
+
+package gosketch
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Alinhamentos de texto usados por TextAlign (além de CENTER)
+// Similar às constantes LEFT, RIGHT, TOP, BOTTOM e BASELINE do p5.js/Processing
+const (
+	LEFT = iota + CENTER + 1
+	RIGHT
+	TOP
+	BOTTOM
+	BASELINE
+)
+
+// Estado global de texto
+var (
+	currentFont font.Face   = defaultFont.face(12)
+	textSize    float64     = 12
+	textColor   color.Color = color.Black
+	textLeading float64     = 15 // Distância entre linhas; 1.25 × textSize por padrão
+	textAlignX  int         = LEFT
+	textAlignY  int         = BASELINE
+	textSpacing float64     = 0 // Espaço extra entre caracteres, em pixels
+)
+
+// Text desenha texto no canvas
+// Quebras de linha ("\n") iniciam novas linhas separadas por TextLeading.
+// Se largura e altura forem passadas, o texto é quebrado por palavras dentro da caixa
+// (x, y, w, h) e linhas que não cabem na altura não são desenhadas.
+// O posicionamento segue TextAlign.
+func Text(str string, x, y float64, box ...float64) {
+	if notRecordable("Text") {
+		return
+	}
+	if canvas == nil {
+		reportError(newError(ErrNoCanvas, "tentativa de desenhar texto sem canvas inicializado"))
+		return
+	}
+
+	if currentFont == nil {
+		reportError(newError(ErrNoFont, "tentativa de desenhar texto sem fonte disponível"))
+		return
+	}
+
+	if len(box) == 1 || (len(box) >= 2 && (box[0] <= 0 || box[1] <= 0)) {
+		reportError(newError(ErrInvalidArgument, "caixa de texto inválida: %v - informe largura e altura positivas", box))
+		return
+	}
+
+	for _, line := range layoutText(str, x, y, box) {
+		drawTextLine(line.text, line.x, line.y)
+	}
+}
+
+// drawTextLine desenha uma linha de texto com a baseline em (x, y)
+// Com espaçamento entre letras, cada caractere é desenhado separadamente.
+func drawTextLine(str string, x, y float64) {
+	// O texto é desenhado com uma face no tamanho físico para manter a nitidez
+	face, d := textDrawFace(), canvas.density
+	if textSpacing == 0 {
+		text.Draw(canvas.img, str, face, int(math.Round(x*d)), int(math.Round(y*d)), textColor)
+		return
+	}
+
+	prev := rune(-1)
+	for _, r := range str {
+		if prev >= 0 {
+			x += fixedToFloat(currentFont.Kern(prev, r)) + textSpacing
+		}
+		text.Draw(canvas.img, string(r), face, int(math.Round(x*d)), int(math.Round(y*d)), textColor)
+		if advance, ok := currentFont.GlyphAdvance(r); ok {
+			x += fixedToFloat(advance)
+		}
+		prev = r
+	}
+}
+
+// textLine é uma linha de texto posicionada pelo layout
+type textLine struct {
+	text  string
+	x, y  float64 // Início da linha na baseline
+	width float64 // Avanço total da linha, incluindo kerning
+}
+
+// layoutText quebra o texto em linhas e calcula a posição de cada uma conforme o alinhamento
+func layoutText(str string, x, y float64, box []float64) []textLine {
+	ascent, descent := TextAscent(), TextDescent()
+
+	var lines []string
+	boxed := len(box) >= 2
+	if boxed {
+		lines = wrapText(str, box[0])
+	} else {
+		lines = strings.Split(str, "\n")
+	}
+
+	// Altura do bloco da primeira linha (topo) até a última (base)
+	blockHeight := ascent + descent + float64(len(lines)-1)*textLeading
+
+	// Posição da baseline da primeira linha
+	var baseline float64
+	if boxed {
+		w, h := box[0], box[1]
+		switch textAlignY {
+		case CENTER:
+			baseline = y + (h-blockHeight)/2 + ascent
+		case BOTTOM:
+			baseline = y + h - blockHeight + ascent
+		default: // TOP e BASELINE começam no topo da caixa
+			baseline = y + ascent
+		}
+
+		switch textAlignX {
+		case CENTER:
+			x += w / 2
+		case RIGHT:
+			x += w
+		}
+	} else {
+		switch textAlignY {
+		case TOP:
+			baseline = y + ascent
+		case CENTER:
+			baseline = y - blockHeight/2 + ascent
+		case BOTTOM:
+			baseline = y - blockHeight + ascent
+		default:
+			baseline = y
+		}
+	}
+
+	result := make([]textLine, 0, len(lines))
+	for i, line := range lines {
+		lineY := baseline + float64(i)*textLeading
+		if boxed && lineY+descent > y+box[1] {
+			break
+		}
+
+		width := measureText(line)
+		lineX := x
+		switch textAlignX {
+		case CENTER:
+			lineX -= width / 2
+		case RIGHT:
+			lineX -= width
+		}
+
+		result = append(result, textLine{text: line, x: lineX, y: lineY, width: width})
+	}
+	return result
+}
+
+// wrapText quebra o texto em linhas que cabem na largura máxima
+// As quebras acontecem entre palavras; palavras maiores que a largura são quebradas por caractere.
+func wrapText(str string, maxWidth float64) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(str, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := ""
+		for _, word := range words {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+
+			if measureText(candidate) <= maxWidth {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+
+			// Palavra sozinha maior que a linha: quebra por caractere
+			for measureText(word) > maxWidth && utf8.RuneCountInString(word) > 1 {
+				cut := len(word)
+				for cut > 0 && measureText(word[:cut]) > maxWidth {
+					_, size := utf8.DecodeLastRuneInString(word[:cut])
+					cut -= size
+				}
+				if cut == 0 {
+					_, cut = utf8.DecodeRuneInString(word)
+				}
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// measureText retorna o avanço do texto com a face atual, incluindo kerning
+// e o espaçamento entre letras
+func measureText(str string) float64 {
+	spacing := 0.0
+	if n := utf8.RuneCountInString(str); n > 1 {
+		spacing = float64(n-1) * textSpacing
+	}
+
+	if currentFont == nil {
+		return float64(len(str))*textSize/2 + spacing // Estimativa sem face disponível
+	}
+	return fixedToFloat(font.MeasureString(currentFont, str)) + spacing
+}
+
+// fixedToFloat converte um valor de ponto fixo 26.6 para float64
+func fixedToFloat(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
+
+// TextSize define o tamanho do texto em pixels
+// A face da fonte atual é recriada no novo tamanho (e mantida em cache) e a
+// entrelinha volta ao padrão de 1.25 × size.
+func TextSize(size float64) {
+	if size <= 0 {
+		reportError(newError(ErrInvalidArgument, "tamanho de texto inválido: %.2f - deve ser positivo", size))
+		return
+	}
+	textSize = size
+	textLeading = size * 1.25
+	updateTextFace()
+}
+
+// TextLeading define a distância vertical entre as linhas de texto em pixels
+// Similar à função textLeading() do p5.js/Processing
+func TextLeading(leading float64) {
+	if leading < 0 {
+		reportError(newError(ErrInvalidArgument, "entrelinha inválida: %.2f - deve ser não-negativa", leading))
+		return
+	}
+	textLeading = leading
+}
+
+// TextLetterSpacing define um espaço extra, em pixels, entre os caracteres do texto
+// Valores negativos aproximam os caracteres. O padrão é 0.
+func TextLetterSpacing(spacing float64) {
+	textSpacing = spacing
+}
+
+// TextAlign define o alinhamento horizontal (LEFT, CENTER ou RIGHT) e,
+// opcionalmente, o vertical (TOP, CENTER, BASELINE ou BOTTOM) do texto
+// Similar à função textAlign() do p5.js/Processing
+func TextAlign(horizontal int, vertical ...int) {
+	switch horizontal {
+	case LEFT, CENTER, RIGHT:
+		textAlignX = horizontal
+	default:
+		reportError(newError(ErrInvalidArgument, "alinhamento horizontal inválido: %d - use LEFT, CENTER ou RIGHT", horizontal))
+	}
+
+	if len(vertical) > 0 {
+		switch vertical[0] {
+		case TOP, CENTER, BASELINE, BOTTOM:
+			textAlignY = vertical[0]
+		default:
+			reportError(newError(ErrInvalidArgument, "alinhamento vertical inválido: %d - use TOP, CENTER, BASELINE ou BOTTOM", vertical[0]))
+		}
+	}
+}
+
+// TextColor define a cor do texto
+func TextColor(c ColorValue) {
+	textColor = ParseColorValue(c)
+}
+
+// TextWidth retorna a largura de um texto com a fonte atual, incluindo kerning
+// Para textos com várias linhas, retorna a largura da linha mais longa.
+func TextWidth(str string) int {
+	width := 0.0
+	for _, line := range strings.Split(str, "\n") {
+		width = math.Max(width, measureText(line))
+	}
+	return int(math.Ceil(width))
+}
+
+// TextHeight retorna a altura de uma linha de texto da fonte atual
+func TextHeight() int {
+	if currentFont == nil {
+		return int(textSize) // Estimativa sem face disponível
+	}
+	metrics := currentFont.Metrics()
+	return int(metrics.Height >> 6) // Converte de fixed.Int26_6 para int
+}
+
+// TextAscent retorna a distância da baseline até o topo dos caracteres mais altos
+// Similar à função textAscent() do p5.js/Processing
+func TextAscent() float64 {
+	if currentFont == nil {
+		return textSize * 0.8 // Estimativa sem face disponível
+	}
+	return fixedToFloat(currentFont.Metrics().Ascent)
+}
+
+// TextDescent retorna a distância da baseline até a base dos caracteres mais baixos
+// Similar à função textDescent() do p5.js/Processing
+func TextDescent() float64 {
+	if currentFont == nil {
+		return textSize * 0.2 // Estimativa sem face disponível
+	}
+	return fixedToFloat(currentFont.Metrics().Descent)
+}
+
+// TextBounds retorna o retângulo exato (x, y, w, h) ocupado pelos pixels do texto
+// se ele fosse desenhado por Text com os mesmos argumentos e alinhamento atual
+// Similar à função textBounds() do p5.js
+func TextBounds(str string, x, y float64, box ...float64) (float64, float64, float64, float64) {
+	if currentFont == nil {
+		reportError(newError(ErrNoFont, "tentativa de medir texto sem fonte disponível"))
+		return x, y, 0, 0
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	// Text desenha cada linha a partir da posição arredondada para o pixel físico
+	d := 1.0
+	if canvas != nil {
+		d = canvas.density
+	}
+	snap := func(v float64) float64 { return math.Round(v*d) / d }
+
+	for _, line := range layoutText(str, x, y, box) {
+		bounds, _ := font.BoundString(currentFont, line.text)
+		if bounds.Empty() {
+			continue
+		}
+		lineX, lineY := snap(line.x), snap(line.y)
+		minX = math.Min(minX, lineX+fixedToFloat(bounds.Min.X))
+		minY = math.Min(minY, lineY+fixedToFloat(bounds.Min.Y))
+		// O espaçamento entre letras desloca o último caractere
+		spacing := float64(utf8.RuneCountInString(line.text)-1) * textSpacing
+		maxX = math.Max(maxX, lineX+fixedToFloat(bounds.Max.X)+spacing)
+		maxY = math.Max(maxY, lineY+fixedToFloat(bounds.Max.Y))
+	}
+
+	if math.IsInf(minX, 1) {
+		return x, y, 0, 0
+	}
+	return minX, minY, maxX - minX, maxY - minY
+}
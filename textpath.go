@@ -0,0 +1,206 @@
+/*
+Projeto: GoSketch - Texto em Caminhos
+Descrição: Caminhos parametrizados por comprimento de arco (polilinhas, curvas de Bézier
+e círculos) e desenho de texto com cada caractere posicionado e girado ao longo deles
+*/
+
+package gosketch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Xistaminose/gosketch/shapes"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Path é um caminho usado por TextOnPath
+// Internamente é uma polilinha com a distância acumulada até cada vértice,
+// o que permite localizar qualquer ponto pelo comprimento de arco.
+type Path struct {
+	points  []shapes.Vertex
+	lengths []float64 // Distância acumulada do início até cada vértice
+	closed  bool
+}
+
+// bezierPathSteps é o número de segmentos usados para aproximar uma curva de Bézier
+const bezierPathSteps = 128
+
+// PolylinePath cria um caminho aberto passando pelos vértices na ordem dada
+func PolylinePath(points ...shapes.Vertex) *Path {
+	if len(points) < 2 {
+		reportError(newError(ErrInvalidArgument, "caminho inválido: são necessários pelo menos 2 vértices, recebidos %d", len(points)))
+		return nil
+	}
+	return newPath(points, false)
+}
+
+// BezierPath cria um caminho a partir de uma curva de Bézier cúbica
+// que vai de (x1, y1) a (x2, y2) com pontos de controle (cx1, cy1) e (cx2, cy2)
+func BezierPath(x1, y1, cx1, cy1, cx2, cy2, x2, y2 float64) *Path {
+	points := make([]shapes.Vertex, bezierPathSteps+1)
+	for i := range points {
+		t := float64(i) / bezierPathSteps
+		u := 1 - t
+		points[i] = shapes.Vertex{
+			X: u*u*u*x1 + 3*u*u*t*cx1 + 3*u*t*t*cx2 + t*t*t*x2,
+			Y: u*u*u*y1 + 3*u*u*t*cy1 + 3*u*t*t*cy2 + t*t*t*y2,
+		}
+	}
+	return newPath(points, false)
+}
+
+// CirclePath cria um caminho fechado sobre um círculo
+// O caminho começa no topo do círculo e segue no sentido horário, de modo que
+// o texto fica em pé na parte de cima, como em selos circulares.
+func CirclePath(cx, cy, radius float64) *Path {
+	if radius <= 0 {
+		reportError(newError(ErrInvalidDimensions, "raio inválido para caminho circular: %.2f - o raio deve ser positivo", radius))
+		return nil
+	}
+
+	// Aproximadamente um segmento a cada 2 pixels de circunferência
+	steps := int(Constrain(math.Ceil(TWO_PI*radius/2), 32, 1024))
+	points := make([]shapes.Vertex, steps)
+	for i := range points {
+		theta := -HALF_PI + TWO_PI*float64(i)/float64(steps)
+		points[i] = shapes.Vertex{X: cx + radius*math.Cos(theta), Y: cy + radius*math.Sin(theta)}
+	}
+	return newPath(points, true)
+}
+
+// newPath calcula a tabela de comprimentos de arco do caminho
+func newPath(points []shapes.Vertex, closed bool) *Path {
+	if closed {
+		points = append(points, points[0])
+	}
+
+	lengths := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		lengths[i] = lengths[i-1] + Dist(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y)
+	}
+	return &Path{points: points, lengths: lengths, closed: closed}
+}
+
+// Length retorna o comprimento total do caminho em pixels
+func (p *Path) Length() float64 {
+	return p.lengths[len(p.lengths)-1]
+}
+
+// PointAt retorna o ponto a uma distância d do início do caminho e a direção
+// da tangente nesse ponto, em graus
+// Em caminhos fechados a distância dá a volta; em caminhos abertos, ok é false
+// quando d está fora do caminho.
+func (p *Path) PointAt(d float64) (x, y, angle float64, ok bool) {
+	length := p.Length()
+	if length == 0 {
+		return p.points[0].X, p.points[0].Y, 0, false
+	}
+
+	if p.closed {
+		d = math.Mod(d, length)
+		if d < 0 {
+			d += length
+		}
+	} else if d < 0 || d > length {
+		return 0, 0, 0, false
+	}
+
+	// Encontra o segmento que contém a distância d
+	i := sort.SearchFloat64s(p.lengths, d)
+	if i == 0 {
+		i = 1
+	}
+	a, b := p.points[i-1], p.points[i]
+	segment := p.lengths[i] - p.lengths[i-1]
+
+	t := 0.0
+	if segment > 0 {
+		t = (d - p.lengths[i-1]) / segment
+	}
+	return Lerp(a.X, b.X, t), Lerp(a.Y, b.Y, t), Degrees(math.Atan2(b.Y-a.Y, b.X-a.X)), true
+}
+
+// TextOnPath desenha o texto ao longo do caminho, com cada caractere centrado sobre
+// o caminho e girado conforme a tangente. offset é a distância, em pixels, a partir
+// do início do caminho onde o texto é ancorado: o alinhamento horizontal de TextAlign
+// define se o texto começa (LEFT), é centrado (CENTER) ou termina (RIGHT) nesse ponto,
+// e o alinhamento vertical define qual parte do texto fica sobre o caminho.
+// O espaçamento de TextLetterSpacing é respeitado. Em caminhos abertos, caracteres
+// cujo avanço ultrapassa, mesmo em parte, as extremidades não são desenhados.
+func TextOnPath(str string, path *Path, offset float64) {
+	if notRecordable("TextOnPath") {
+		return
+	}
+	if canvas == nil {
+		reportError(newError(ErrNoCanvas, "tentativa de desenhar texto sem canvas inicializado"))
+		return
+	}
+
+	if path == nil {
+		reportError(newError(ErrNilArgument, "tentativa de desenhar texto em um caminho nulo"))
+		return
+	}
+
+	if currentFont == nil {
+		reportError(newError(ErrNoFont, "tentativa de desenhar texto sem fonte disponível"))
+		return
+	}
+
+	start := offset
+	switch textAlignX {
+	case CENTER:
+		start -= measureText(str) / 2
+	case RIGHT:
+		start -= measureText(str)
+	}
+
+	// Deslocamento perpendicular para que a parte escolhida do texto fique sobre o caminho
+	ascent, descent := TextAscent(), TextDescent()
+	shift := 0.0
+	switch textAlignY {
+	case TOP:
+		shift = ascent
+	case CENTER:
+		shift = (ascent - descent) / 2
+	case BOTTOM:
+		shift = -descent
+	}
+
+	pen := start
+	prev := rune(-1)
+	for _, r := range str {
+		if prev >= 0 {
+			pen += fixedToFloat(currentFont.Kern(prev, r)) + textSpacing
+		}
+		prev = r
+
+		advance := 0.0
+		if a, ok := currentFont.GlyphAdvance(r); ok {
+			advance = fixedToFloat(a)
+		}
+
+		// Em caminhos abertos, o avanço inteiro do caractere precisa caber no caminho
+		begin := pen
+		pen += advance
+		if !path.closed && (begin < 0 || pen > path.Length()) {
+			continue
+		}
+
+		// O caractere é posicionado e girado pelo seu centro
+		x, y, angle, ok := path.PointAt(begin + advance/2)
+		if !ok {
+			continue
+		}
+
+		// O glifo é desenhado com a face no tamanho físico do canvas
+		d := canvas.density
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(-advance/2*d, shift*d)
+		opts.GeoM.Rotate(Radians(angle))
+		opts.GeoM.Translate(x*d, y*d)
+		opts.ColorScale.ScaleWithColor(textColor)
+		text.DrawWithOptions(canvas.img, string(r), textDrawFace(), opts)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Projeto: GoSketch - Texto como Geometria
+Descrição: Conversão de texto em contornos vetoriais a partir dos glifos da fonte,
+para preenchimento, contorno, plotagem ou como alvo de partículas
+*/
+
+package gosketch
+
+import (
+	"math"
+	"strings"
+
+	"github.com/Xistaminose/gosketch/shapes"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextPoint é um ponto amostrado do contorno de um texto
+type TextPoint struct {
+	X, Y  float64
+	Angle float64 // Direção da tangente ao contorno, em graus
+}
+
+// TextToPoints retorna pontos distribuídos uniformemente sobre os contornos do texto
+// desenhado com a fonte atual no tamanho size, com a baseline em (x, y). A fonte
+// padrão, que é bitmap, usa os contornos da Go Regular.
+// sampleFactor controla a densidade: a distância entre pontos é 1/sampleFactor pixels
+// (0.1 gera um ponto a cada 10 pixels). O alinhamento segue TextAlign.
+// Similar à função textToPoints() do p5.js
+func TextToPoints(str string, x, y, size, sampleFactor float64) []TextPoint {
+	if sampleFactor <= 0 {
+		reportError(newError(ErrInvalidArgument, "fator de amostragem inválido: %.2f - deve ser positivo", sampleFactor))
+		return nil
+	}
+
+	contours := textContours(str, x, y, size)
+	spacing := 1 / sampleFactor
+
+	var points []TextPoint
+	for _, contour := range contours {
+		points = append(points, sampleContour(contour, spacing)...)
+	}
+	return points
+}
+
+// TextToShape retorna os contornos do texto como um polígono que pode ser desenhado
+// por RenderShape, usando a fonte atual no tamanho size com a baseline em (x, y).
+// Os buracos das letras (como em "o" e "A") são preservados pela regra de preenchimento.
+func TextToShape(str string, x, y, size float64) *shapes.PolygonShape {
+	return shapes.NewPolygon(textContours(str, x, y, size)...)
+}
+
+// textContours converte o texto em contornos fechados de vértices
+// Curvas quadráticas e cúbicas dos glifos são aproximadas por segmentos de reta.
+func textContours(str string, x, y, size float64) [][]shapes.Vertex {
+	if size <= 0 {
+		reportError(newError(ErrInvalidArgument, "tamanho de texto inválido: %.2f - deve ser positivo", size))
+		return nil
+	}
+
+	// A fonte padrão é bitmap; seus contornos vêm da Go Regular
+	f := textFont
+	if f == defaultFont {
+		f = defaultOutlineFont
+	}
+	if f == nil || f.sfnt == nil {
+		reportError(newError(ErrNoFont, "a fonte atual não possui contornos vetoriais"))
+		return nil
+	}
+
+	var buf sfnt.Buffer
+	ppem := fixed.Int26_6(math.Round(size * 64))
+	lines := strings.Split(str, "\n")
+	// A distância entre linhas é a de TextLeading, proporcional ao tamanho pedido
+	leading := textLeading * size / textSize
+
+	// Métricas da fonte no tamanho pedido, para o alinhamento vertical
+	metrics, err := f.sfnt.Metrics(&buf, ppem, font.HintingNone)
+	if err != nil {
+		reportError(newError(ErrLoadFailed, "erro ao ler métricas da fonte '%s': %w", f.name, err))
+		return nil
+	}
+	ascent, descent := fixedToFloat(metrics.Ascent), fixedToFloat(metrics.Descent)
+	blockHeight := ascent + descent + float64(len(lines)-1)*leading
+
+	baseline := y
+	switch textAlignY {
+	case TOP:
+		baseline = y + ascent
+	case CENTER:
+		baseline = y - blockHeight/2 + ascent
+	case BOTTOM:
+		baseline = y - blockHeight + ascent
+	}
+
+	var contours [][]shapes.Vertex
+	for i, line := range lines {
+		lineContours, width := glyphContours(f, &buf, line, ppem)
+
+		dx := x
+		switch textAlignX {
+		case CENTER:
+			dx -= width / 2
+		case RIGHT:
+			dx -= width
+		}
+		dy := baseline + float64(i)*leading
+
+		for _, contour := range lineContours {
+			for j := range contour {
+				contour[j].X += dx
+				contour[j].Y += dy
+			}
+			contours = append(contours, contour)
+		}
+	}
+	return contours
+}
+
+// glyphContours retorna os contornos de uma linha de texto com origem na baseline
+// e o avanço total da linha, incluindo kerning
+func glyphContours(f *Font, buf *sfnt.Buffer, line string, ppem fixed.Int26_6) ([][]shapes.Vertex, float64) {
+	var contours [][]shapes.Vertex
+	pen := 0.0
+	prev := sfnt.GlyphIndex(0)
+
+	for i, r := range []rune(line) {
+		glyph, err := f.sfnt.GlyphIndex(buf, r)
+		if err != nil {
+			continue
+		}
+
+		if i > 0 {
+			// Fontes sem tabela de kerning retornam erro, que é ignorado
+			if kern, err := f.sfnt.Kern(buf, prev, glyph, ppem, font.HintingNone); err == nil {
+				pen += fixedToFloat(kern)
+			}
+		}
+		prev = glyph
+
+		segments, err := f.sfnt.LoadGlyph(buf, glyph, ppem, nil)
+		if err == nil {
+			contours = append(contours, flattenSegments(segments, pen)...)
+		}
+
+		if advance, err := f.sfnt.GlyphAdvance(buf, glyph, ppem, font.HintingNone); err == nil {
+			pen += fixedToFloat(advance)
+		}
+	}
+	return contours, pen
+}
+
+// flattenSegments converte os segmentos de um glifo em contornos poligonais
+func flattenSegments(segments sfnt.Segments, offsetX float64) [][]shapes.Vertex {
+	var contours [][]shapes.Vertex
+	var current []shapes.Vertex
+
+	point := func(p fixed.Point26_6) shapes.Vertex {
+		return shapes.Vertex{X: offsetX + fixedToFloat(p.X), Y: fixedToFloat(p.Y)}
+	}
+	closeContour := func() {
+		if len(current) > 2 {
+			contours = append(contours, current)
+		}
+		current = nil
+	}
+
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			closeContour()
+			current = append(current, point(seg.Args[0]))
+		case sfnt.SegmentOpLineTo:
+			current = append(current, point(seg.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			p0 := current[len(current)-1]
+			p1, p2 := point(seg.Args[0]), point(seg.Args[1])
+			steps := curveSteps(p0, p1, p2)
+			for i := 1; i <= steps; i++ {
+				t := float64(i) / float64(steps)
+				u := 1 - t
+				current = append(current, shapes.Vertex{
+					X: u*u*p0.X + 2*u*t*p1.X + t*t*p2.X,
+					Y: u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y,
+				})
+			}
+		case sfnt.SegmentOpCubeTo:
+			p0 := current[len(current)-1]
+			p1, p2, p3 := point(seg.Args[0]), point(seg.Args[1]), point(seg.Args[2])
+			steps := curveSteps(p0, p1, p2, p3)
+			for i := 1; i <= steps; i++ {
+				t := float64(i) / float64(steps)
+				u := 1 - t
+				current = append(current, shapes.Vertex{
+					X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+					Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+				})
+			}
+		}
+	}
+	closeContour()
+
+	// O último vértice repete o primeiro quando o contorno é fechado explicitamente
+	for i, contour := range contours {
+		first, last := contour[0], contour[len(contour)-1]
+		if first == last {
+			contours[i] = contour[:len(contour)-1]
+		}
+	}
+	return contours
+}
+
+// curveSteps estima em quantos segmentos uma curva deve ser dividida
+// a partir do comprimento do seu polígono de controle (cerca de um segmento a cada 2 pixels)
+func curveSteps(points ...shapes.Vertex) int {
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		length += Dist(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y)
+	}
+	steps := int(math.Ceil(length / 2))
+	return int(Constrain(float64(steps), 2, 64))
+}
+
+// sampleContour distribui pontos a cada spacing pixels ao longo de um contorno fechado
+func sampleContour(contour []shapes.Vertex, spacing float64) []TextPoint {
+	var points []TextPoint
+	carry := 0.0 // Distância percorrida desde o último ponto amostrado
+
+	for i := range contour {
+		a, b := contour[i], contour[(i+1)%len(contour)]
+		length := Dist(a.X, a.Y, b.X, b.Y)
+		if length == 0 {
+			continue
+		}
+		angle := Degrees(math.Atan2(b.Y-a.Y, b.X-a.X))
+
+		// Posição do próximo ponto ao longo deste segmento; o primeiro ponto fica no
+		// início do primeiro segmento não degenerado
+		d := 0.0
+		if len(points) > 0 {
+			d = spacing - carry
+		}
+		for ; d < length; d += spacing {
+			t := d / length
+			points = append(points, TextPoint{
+				X:     Lerp(a.X, b.X, t),
+				Y:     Lerp(a.Y, b.Y, t),
+				Angle: angle,
+			})
+		}
+		carry = length - (d - spacing)
+	}
+	return points
+}
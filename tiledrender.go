@@ -0,0 +1,149 @@
+/*
+Projeto: GoSketch - Renderização em Blocos
+Descrição: Renderização de pôsteres maiores que o limite de texturas da GPU: as formas
+de draw são gravadas uma vez, cada faixa da imagem é rasterizada em memória e as
+faixas são gravadas em sequência em um único PNG, sem manter a imagem inteira na memória
+*/
+
+package gosketch
+
+import (
+	"bufio"
+	"encoding/binary"
+	"image"
+	"io"
+	"os"
+
+	"github.com/Xistaminose/gosketch/shapes"
+)
+
+// idatChunkSize é o tamanho máximo dos blocos IDAT escritos por RenderTiled
+const idatChunkSize = 64 << 10
+
+// RenderTiled renderiza a função registrada com Draw em um PNG de w x h pixels,
+// com as coordenadas do sketch multiplicadas por scale (por exemplo, um canvas de
+// 1000x1000 com scale 20 gera um pôster de 20000x20000)
+// draw é executada uma única vez e suas formas são escaladas; a imagem é então dividida
+// em faixas horizontais, cada uma rasterizada em paralelo apenas nas suas linhas e
+// comprimida e escrita em out antes da faixa seguinte. Nem a GPU nem a memória precisam
+// comportar a imagem inteira, e todas as faixas vêm do mesmo quadro, mesmo que draw use
+// números aleatórios ou mude de estado.
+// Como em RecordDisplayList, apenas formas e Background entram na imagem; texto,
+// imagens e pixels desenhados por draw são ignorados e reportam ErrUnsupported.
+// Também como RecordDisplayList, deve ser chamada na goroutine principal ou por QueueDraw.
+func RenderTiled(w, h int, scale float64, out string) error {
+	if w <= 0 || h <= 0 {
+		return newError(ErrInvalidDimensions, "dimensões inválidas para renderização em blocos: %dx%d - as dimensões devem ser positivas", w, h)
+	}
+	if scale <= 0 {
+		return newError(ErrInvalidArgument, "escala inválida: %.2f - a escala deve ser positiva", scale)
+	}
+	if drawFn == nil {
+		return newError(ErrInvalidState, "RenderTiled requer uma função registrada com Draw")
+	}
+	if displayList != nil {
+		return newError(ErrInvalidState, "RenderTiled não pode ser chamada durante RecordDisplayList")
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return newError(ErrWriteFailed, "erro ao criar arquivo '%s': %w", out, err)
+	}
+	defer file.Close()
+
+	if err := renderTiledPNG(file, w, h, scale); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return newError(ErrWriteFailed, "erro ao escrever arquivo '%s': %w", out, err)
+	}
+	return nil
+}
+
+// renderTiledPNG escreve o PNG faixa por faixa
+func renderTiledPNG(file io.Writer, w, h int, scale float64) error {
+	// As formas são gravadas uma única vez: executar draw de novo a cada faixa
+	// mudaria o quadro entre as faixas se draw dependesse de estado ou de random
+	list := RecordDisplayList(w, h, drawFn)
+	if list == nil {
+		return newError(ErrInvalidState, "falha ao gravar as formas do quadro")
+	}
+	scaled, err := list.scaled(scale)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(file)
+	out.WriteString(pngSignature)
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(w))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(h))
+	ihdr[8] = 8 // Bits por canal
+	ihdr[9] = 6 // Tipo de cor: RGBA
+	writePNGChunk(out, "IHDR", ihdr)
+	if meta := currentMetadata(); meta != nil {
+		for _, chunk := range meta.pngChunks() {
+			writePNGChunk(out, chunk.typ, chunk.data)
+		}
+	}
+
+	// Os dados comprimidos são divididos em blocos IDAT à medida que são produzidos
+	idat := bufio.NewWriterSize(&pngChunkWriter{w: out, typ: "IDAT"}, idatChunkSize)
+	enc := newPNGRowEncoder(idat, w)
+
+	for y := 0; y < h; y += defaultTileSize {
+		band := image.Rect(0, y, w, min(y+defaultTileSize, h))
+
+		// A faixa usa as coordenadas da imagem completa, então as formas são
+		// rasterizadas exatamente como seriam em uma única imagem, sem emendas
+		img := image.NewRGBA(band)
+		scaled.rasterizeTiles(img, defaultTileSize)
+		if err := enc.writeRows(img); err != nil {
+			return newError(ErrWriteFailed, "erro ao codificar faixa %v: %w", band, err)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return newError(ErrWriteFailed, "erro ao codificar imagem: %w", err)
+	}
+	if err := idat.Flush(); err != nil {
+		return newError(ErrWriteFailed, "erro ao escrever imagem: %w", err)
+	}
+	writePNGChunk(out, "IEND", nil)
+	if err := out.Flush(); err != nil {
+		return newError(ErrWriteFailed, "erro ao escrever imagem: %w", err)
+	}
+	return nil
+}
+
+// scaled retorna uma cópia da lista com as formas e as espessuras de contorno
+// multiplicadas por scale
+func (l *DisplayList) scaled(scale float64) (*DisplayList, error) {
+	result := &DisplayList{Width: l.Width, Height: l.Height, commands: make([]drawCommand, len(l.commands))}
+	for i, cmd := range l.commands {
+		if cmd.shape != nil {
+			s, ok := shapes.Transform(cmd.shape, scale, 0, 0)
+			if !ok {
+				return nil, newError(ErrUnsupported, "a forma %T não implementa shapes.Transformable e não pode ser escalada", cmd.shape)
+			}
+			cmd.shape = s
+			cmd.strokeWeight *= scale
+			cmd.bounds, cmd.bounded = shapes.Bounds(s, cmd.strokeWeight)
+		}
+		result.commands[i] = cmd
+	}
+	return result, nil
+}
+
+// pngChunkWriter escreve cada chamada de Write como um bloco PNG do tipo typ
+type pngChunkWriter struct {
+	w   io.Writer
+	typ string
+}
+
+// Write implementa io.Writer
+func (c *pngChunkWriter) Write(p []byte) (int, error) {
+	writePNGChunk(c.w, c.typ, p)
+	return len(p), nil
+}
@@ -0,0 +1,105 @@
+package gosketch
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderTiledBandsMatchRasterize(t *testing.T) {
+	resetStyle(t)
+	list := recordTestScene(t)
+
+	// Escalar e rasterizar por faixas, como RenderTiled, dá o mesmo resultado que
+	// escalar e rasterizar a imagem inteira de uma vez
+	const scale = 1.5
+	scaled, err := list.scaled(scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scaled.Width = int(math.Ceil(float64(list.Width) * scale))
+	scaled.Height = int(math.Ceil(float64(list.Height) * scale))
+	want := Rasterize(scaled)
+
+	for _, bandHeight := range []int{1, 13, 64} {
+		got := image.NewRGBA(want.Rect)
+		for y := 0; y < scaled.Height; y += bandHeight {
+			band := image.Rect(0, y, scaled.Width, min(y+bandHeight, scaled.Height))
+			img := got.SubImage(band).(*image.RGBA)
+			scaled.rasterizeTiles(img, 32)
+		}
+		t.Run(fmt.Sprintf("faixas=%d", bandHeight), func(t *testing.T) {
+			comparePixels(t, want, got)
+		})
+	}
+}
+
+func TestRenderTiledRecordsDrawOnce(t *testing.T) {
+	resetStyle(t)
+	previous := drawFn
+	t.Cleanup(func() { drawFn = previous })
+
+	// Cada execução de draw desenha com uma cor diferente; se draw rodasse de novo
+	// a cada faixa, as faixas teriam cores diferentes
+	calls := 0
+	drawFn = func() {
+		calls++
+		NoStroke()
+		FillColor(color.RGBA{uint8(calls * 40), 0, 0, 255})
+		Rectangle(0, 0, 40, 700)
+	}
+
+	out := filepath.Join(t.TempDir(), "tiled.png")
+	if err := RenderTiled(40, defaultTileSize*2+10, 1, out); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("draw executada %d vezes; esperado 1", calls)
+	}
+
+	file, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := color.RGBAModel.Convert(img.At(20, 0))
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		if c := color.RGBAModel.Convert(img.At(20, y)); c != first {
+			t.Fatalf("linha %d = %v; esperado %v em todas as faixas", y, c, first)
+		}
+	}
+}
+
+func TestRecordingReportsUnrecordableCalls(t *testing.T) {
+	resetStyle(t)
+	var unsupported []error
+	captureErrors(t, func(err error) {
+		if errors.Is(err, ErrUnsupported) {
+			unsupported = append(unsupported, err)
+		}
+	})
+
+	list := RecordDisplayList(50, 50, func() {
+		Rectangle(0, 0, 10, 10)
+		Text("ignorado", 10, 10)
+		SetPixel(1, 1, Color(255))
+		UpdatePixels()
+	})
+
+	if list.Len() != 1 {
+		t.Errorf("lista com %d comandos; esperado apenas o retângulo", list.Len())
+	}
+	if len(unsupported) != 3 {
+		t.Errorf("%d erros ErrUnsupported reportados; esperado 3: %v", len(unsupported), unsupported)
+	}
+}
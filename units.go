@@ -0,0 +1,195 @@
+/*
+Projeto: GoSketch - Unidades Físicas
+Descrição: Canvas em formatos de papel com resolução em DPI, coordenadas e espessuras
+em milímetros, centímetros ou polegadas e a resolução gravada nas imagens salvas
+*/
+
+package gosketch
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/Xistaminose/gosketch/shapes"
+)
+
+// Unidades de medida para coordenadas e espessuras
+const (
+	PX   = iota // Pixels do canvas (padrão)
+	MM          // Milímetros
+	CM          // Centímetros
+	INCH        // Polegadas
+)
+
+// Orientações de papel
+const (
+	PORTRAIT = iota
+	LANDSCAPE
+)
+
+// defaultDPI é a resolução usada para converter unidades físicas quando
+// nenhuma foi definida (a mesma do pixel de referência do CSS)
+const defaultDPI = 96
+
+// PaperSize é um formato de papel, com dimensões em milímetros na orientação retrato
+type PaperSize struct {
+	Width, Height float64
+}
+
+// Formatos de papel ISO 216 e norte-americanos
+var (
+	A0      = PaperSize{841, 1189}
+	A1      = PaperSize{594, 841}
+	A2      = PaperSize{420, 594}
+	A3      = PaperSize{297, 420}
+	A4      = PaperSize{210, 297}
+	A5      = PaperSize{148, 210}
+	A6      = PaperSize{105, 148}
+	LETTER  = PaperSize{215.9, 279.4}
+	LEGAL   = PaperSize{215.9, 355.6}
+	TABLOID = PaperSize{279.4, 431.8}
+)
+
+// Estado global de unidades
+var (
+	unitMode  = PX
+	canvasDPI float64 // Resolução do canvas; 0 quando não definida
+
+	unscaledShapeTypes sync.Map // Tipos de forma já reportados por unitShape (chave: %T)
+)
+
+// CreateCanvasPaper cria um canvas do tamanho de um formato de papel na resolução dpi
+// Por exemplo, CreateCanvasPaper(A3, PORTRAIT, 300) cria um canvas de 3508x4961 pixels.
+// A resolução é gravada nas imagens salvas com SaveImage. Use Units(MM) para desenhar
+// em milímetros.
+func CreateCanvasPaper(paper PaperSize, orientation int, dpi float64) {
+	if paper.Width <= 0 || paper.Height <= 0 {
+		reportError(newError(ErrInvalidDimensions, "formato de papel inválido: %.1fx%.1f mm - as dimensões devem ser positivas", paper.Width, paper.Height))
+		return
+	}
+	if dpi <= 0 {
+		reportError(newError(ErrInvalidArgument, "resolução inválida: %.2f dpi - a resolução deve ser positiva", dpi))
+		return
+	}
+
+	w, h := paper.Width, paper.Height
+	switch orientation {
+	case PORTRAIT:
+	case LANDSCAPE:
+		w, h = h, w
+	default:
+		reportError(newError(ErrInvalidArgument, "orientação inválida: %d - use PORTRAIT ou LANDSCAPE", orientation))
+		return
+	}
+
+	pw, ph := int(math.Round(w/25.4*dpi)), int(math.Round(h/25.4*dpi))
+	if pw <= 0 || ph <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões de canvas inválidas: %dx%d - as dimensões devem ser positivas", pw, ph))
+		return
+	}
+
+	// A resolução só é definida depois que o canvas existe, já que CreateCanvas a reinicia
+	CreateCanvas(pw, ph)
+	canvasDPI = dpi
+}
+
+// DPI define a resolução do canvas em pontos por polegada, usada para converter
+// unidades físicas e gravada nas imagens salvas
+// Chame depois de CreateCanvas, que volta à resolução padrão.
+func DPI(dpi float64) {
+	if dpi <= 0 {
+		reportError(newError(ErrInvalidArgument, "resolução inválida: %.2f dpi - a resolução deve ser positiva", dpi))
+		return
+	}
+	canvasDPI = dpi
+}
+
+// GetDPI retorna a resolução do canvas (96 se nenhuma foi definida)
+func GetDPI() float64 {
+	if canvasDPI > 0 {
+		return canvasDPI
+	}
+	return defaultDPI
+}
+
+// Units define a unidade das coordenadas e espessuras: PX, MM, CM ou INCH
+// A conversão usa a resolução de GetDPI e vale para formas, StrokeWeight e a posição
+// e as dimensões de Image. Texto continua em pixels; use UnitsToPixels para posicioná-lo.
+func Units(mode int) {
+	switch mode {
+	case PX, MM, CM, INCH:
+		unitMode = mode
+	default:
+		reportError(newError(ErrInvalidArgument, "unidade inválida: %d - use PX, MM, CM ou INCH", mode))
+	}
+}
+
+// GetUnits retorna a unidade atual das coordenadas
+func GetUnits() int {
+	return unitMode
+}
+
+// CanvasSize retorna as dimensões do canvas na unidade atual
+// Com Units(MM) em um canvas criado por CreateCanvasPaper(A4, PORTRAIT, dpi),
+// retorna aproximadamente 210 x 297.
+func CanvasSize() (float64, float64) {
+	if canvas == nil {
+		reportError(newError(ErrNoCanvas, "tentativa de obter dimensões sem canvas inicializado"))
+		return 0, 0
+	}
+	return PixelsToUnits(float64(canvas.Width)), PixelsToUnits(float64(canvas.Height))
+}
+
+// unitScale retorna quantos pixels lógicos correspondem a uma unidade atual
+func unitScale() float64 {
+	switch unitMode {
+	case MM:
+		return GetDPI() / 25.4
+	case CM:
+		return GetDPI() / 2.54
+	case INCH:
+		return GetDPI()
+	}
+	return 1
+}
+
+// UnitsToPixels converte um valor da unidade atual para pixels do canvas
+func UnitsToPixels(v float64) float64 {
+	return toPixels(v)
+}
+
+// PixelsToUnits converte um valor em pixels do canvas para a unidade atual
+func PixelsToUnits(v float64) float64 {
+	return v / unitScale()
+}
+
+// toPixels converte um valor da unidade atual para pixels lógicos
+func toPixels(v float64) float64 {
+	return v * unitScale()
+}
+
+// imageDPI retorna a resolução das imagens salvas, considerando a densidade de pixels,
+// e se ela foi definida
+func imageDPI() (float64, bool) {
+	if canvasDPI <= 0 || canvas == nil {
+		return 0, false
+	}
+	return canvasDPI * canvas.density, true
+}
+
+// unitShape converte a forma da unidade atual para pixels lógicos
+func unitShape(s shapes.Shape) shapes.Shape {
+	k := unitScale()
+	if k == 1 {
+		return s
+	}
+	scaled, ok := shapes.Transform(s, k, 0, 0)
+	// O erro se repetiria a cada quadro, então é reportado uma vez por tipo de forma
+	if !ok {
+		if _, reported := unscaledShapeTypes.LoadOrStore(fmt.Sprintf("%T", s), true); !reported {
+			reportError(newError(ErrUnsupported, "a forma %T não implementa shapes.Transformable e será desenhada em pixels", s))
+		}
+	}
+	return scaled
+}
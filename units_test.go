@@ -0,0 +1,75 @@
+package gosketch
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/Xistaminose/gosketch/shapes"
+)
+
+// resetCanvas restaura o canvas, a resolução e as unidades ao final do teste
+func resetCanvas(t *testing.T) {
+	t.Helper()
+	previous, dpi, units := canvas, canvasDPI, unitMode
+	t.Cleanup(func() {
+		canvas, canvasDPI, unitMode = previous, dpi, units
+	})
+}
+
+func TestCreateCanvasResetsDPI(t *testing.T) {
+	resetCanvas(t)
+
+	CreateCanvasPaper(A6, PORTRAIT, 150)
+	if GetDPI() != 150 || canvas.Width != 620 || canvas.Height != 874 {
+		t.Fatalf("CreateCanvasPaper: %dx%d a %v dpi; esperado 620x874 a 150 dpi", canvas.Width, canvas.Height, GetDPI())
+	}
+
+	CreateCanvas(200, 100)
+	if GetDPI() != defaultDPI {
+		t.Errorf("GetDPI após CreateCanvas = %v; esperado %v", GetDPI(), float64(defaultDPI))
+	}
+	if _, ok := imageDPI(); ok {
+		t.Error("a resolução do canvas anterior seria gravada nas imagens")
+	}
+}
+
+func TestCreateCanvasPaperFailureKeepsState(t *testing.T) {
+	resetCanvas(t)
+	var errs []error
+	captureErrors(t, func(err error) { errs = append(errs, err) })
+
+	CreateCanvas(200, 100)
+	before := canvas
+
+	// Uma resolução tão baixa gera um canvas sem pixels
+	CreateCanvasPaper(A6, PORTRAIT, 0.01)
+	if canvas != before || GetDPI() != defaultDPI {
+		t.Errorf("CreateCanvasPaper inválida alterou o canvas ou a resolução: %v dpi", GetDPI())
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidDimensions) {
+		t.Errorf("erros reportados = %v; esperado um ErrInvalidDimensions", errs)
+	}
+}
+
+// fixedShape é uma forma que não implementa shapes.Transformable
+type fixedShape struct{}
+
+func (fixedShape) Draw(shapes.Canvas, color.Color, color.Color, bool, bool, float64) {}
+
+func TestUnitShapeReportsOncePerType(t *testing.T) {
+	resetCanvas(t)
+	var errs []error
+	captureErrors(t, func(err error) { errs = append(errs, err) })
+	t.Cleanup(func() { unscaledShapeTypes.Delete("gosketch.fixedShape") })
+
+	Units(MM)
+	for i := 0; i < 10; i++ {
+		if s := unitShape(fixedShape{}); s != (fixedShape{}) {
+			t.Fatalf("unitShape retornou %v; esperado a forma original", s)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrUnsupported) {
+		t.Errorf("erros reportados = %v; esperado um único ErrUnsupported", errs)
+	}
+}
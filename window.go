@@ -0,0 +1,210 @@
+/*
+Projeto: GoSketch - Janela
+Descrição: Título, tela cheia e redimensionamento da janela, redimensionamento do canvas
+em tempo de execução e políticas de escala do canvas na janela (esticar, letterbox e
+escala inteira de pixels) para instalações em tela cheia
+*/
+
+package gosketch
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Políticas de escala do canvas dentro da janela
+const (
+	LETTERBOX     = iota // Mantém a proporção do canvas, com faixas pretas nas sobras
+	STRETCH              // Estica o canvas para ocupar toda a janela, sem manter a proporção
+	INTEGER_SCALE        // Maior escala inteira que cabe na janela, centralizada (pixel art)
+)
+
+// Estado global da janela
+var (
+	windowTitle     = "Arte Generativa (Go + p5.js API)"
+	windowScaling   = LETTERBOX
+	windowSmoothing bool // Interpolação linear ao escalar o canvas (padrão: pixels nítidos)
+	windowWidth     int
+	windowHeight    int
+	windowResizedFn func()
+
+	windowResizePending bool // A janela mudou de tamanho; WindowResized é chamada no próximo Update
+
+	// Transformação atual do canvas para a tela, calculada em Layout
+	screenScaleX, screenScaleY   float64 = 1, 1
+	screenOffsetX, screenOffsetY float64
+	screenDeviceScale            float64 = 1 // Pixels do dispositivo por unidade da janela
+)
+
+// WindowTitle define o título da janela
+func WindowTitle(title string) {
+	windowTitle = title
+	ebiten.SetWindowTitle(title)
+}
+
+// Fullscreen ativa ou desativa o modo de tela cheia
+// Similar à função fullscreen() do p5.js
+func Fullscreen(enabled bool) {
+	ebiten.SetFullscreen(enabled)
+}
+
+// IsFullscreen retorna se a janela está em tela cheia
+func IsFullscreen() bool {
+	return ebiten.IsFullscreen()
+}
+
+// WindowResizable define se o usuário pode redimensionar a janela
+// O canvas mantém seu tamanho e é escalado conforme a política de WindowScaling;
+// para acompanhar o tamanho da janela, use ResizeCanvas dentro de WindowResized.
+func WindowResizable(enabled bool) {
+	if enabled {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	} else {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeDisabled)
+	}
+}
+
+// WindowScaling define como o canvas é escalado para ocupar a janela:
+// LETTERBOX (padrão), STRETCH ou INTEGER_SCALE
+func WindowScaling(mode int) {
+	switch mode {
+	case LETTERBOX, STRETCH, INTEGER_SCALE:
+		windowScaling = mode
+	default:
+		reportError(newError(ErrInvalidArgument, "política de escala inválida: %d - use LETTERBOX, STRETCH ou INTEGER_SCALE", mode))
+	}
+}
+
+// WindowSmoothing ativa ou desativa a interpolação linear ao escalar o canvas na janela
+// Por padrão os pixels são ampliados sem interpolação, mantendo a pixel art nítida;
+// ative para suavizar escalas não inteiras de LETTERBOX e STRETCH. INTEGER_SCALE
+// nunca interpola.
+func WindowSmoothing(enabled bool) {
+	windowSmoothing = enabled
+}
+
+// WindowResized registra uma função chamada quando a janela muda de tamanho
+// Similar à função windowResized() do p5.js
+func WindowResized(f func()) { windowResizedFn = f }
+
+// WindowWidth retorna a largura atual da janela
+// Similar à variável windowWidth do p5.js
+func WindowWidth() int { return windowWidth }
+
+// WindowHeight retorna a altura atual da janela
+// Similar à variável windowHeight do p5.js
+func WindowHeight() int { return windowHeight }
+
+// ResizeCanvas altera o tamanho do canvas em tempo de execução
+// O conteúdo atual é preservado no canto superior esquerdo; áreas novas ficam transparentes.
+// Em janelas não redimensionáveis, a janela acompanha o novo tamanho.
+// Similar à função resizeCanvas() do p5.js
+func ResizeCanvas(w, h int) {
+	if canvas == nil {
+		reportError(newError(ErrNoCanvas, "tentativa de redimensionar canvas sem canvas inicializado"))
+		return
+	}
+
+	if w <= 0 || h <= 0 {
+		reportError(newError(ErrInvalidDimensions, "dimensões de canvas inválidas: %dx%d - as dimensões devem ser positivas", w, h))
+		return
+	}
+
+	if w == canvas.Width && h == canvas.Height {
+		return
+	}
+
+	if IsRecording() {
+		reportError(newError(ErrRecording, "não é possível redimensionar o canvas durante uma gravação - use StopRecording antes"))
+		return
+	}
+
+	img := ebiten.NewImage(physicalSize(w, canvas.density), physicalSize(h, canvas.density))
+	img.DrawImage(canvas.img, nil)
+	canvas.img.Deallocate()
+	canvas.img = img
+	canvas.Width, canvas.Height = w, h
+
+	// O array de pixels se refere ao tamanho antigo
+	pixels = nil
+
+	if ebiten.WindowResizingMode() == ebiten.WindowResizingModeDisabled && !ebiten.IsFullscreen() {
+		ebiten.SetWindowSize(w, h)
+	}
+}
+
+// updateWindowLayout registra o tamanho da janela e recalcula a transformação do
+// canvas para a tela
+// Chamada dentro de Layout do Ebiten, onde o canvas não pode ser realocado: uma
+// mudança de tamanho apenas agenda WindowResized para o próximo Update.
+func updateWindowLayout(outsideWidth, outsideHeight int) {
+	if windowWidth != 0 && (outsideWidth != windowWidth || outsideHeight != windowHeight) {
+		windowResizePending = true
+	}
+	windowWidth, windowHeight = outsideWidth, outsideHeight
+	screenDeviceScale = DisplayDensity()
+	updateScreenTransform()
+}
+
+// dispatchWindowResized dispara WindowResized se a janela mudou de tamanho desde o
+// último Update e recalcula a transformação, já que o callback pode redimensionar o canvas
+func dispatchWindowResized() {
+	if !windowResizePending {
+		return
+	}
+	windowResizePending = false
+	callHandler("windowResized", windowResizedFn)
+	updateScreenTransform()
+}
+
+// updateScreenTransform calcula a escala e o deslocamento do canvas na janela
+// conforme a política de WindowScaling
+func updateScreenTransform() {
+	if canvas == nil {
+		return
+	}
+
+	cw, ch := float64(canvas.Width), float64(canvas.Height)
+	ww, wh := float64(windowWidth), float64(windowHeight)
+
+	switch windowScaling {
+	case STRETCH:
+		screenScaleX, screenScaleY = ww/cw, wh/ch
+	case INTEGER_SCALE:
+		scale := math.Floor(math.Min(ww/cw, wh/ch))
+		if scale < 1 {
+			// Janelas menores que o canvas: reduz sem arredondar
+			scale = math.Min(ww/cw, wh/ch)
+		}
+		screenScaleX, screenScaleY = scale, scale
+	default:
+		scale := math.Min(ww/cw, wh/ch)
+		screenScaleX, screenScaleY = scale, scale
+	}
+
+	// Centraliza em pixels inteiros para não borrar a escala inteira
+	screenOffsetX = math.Floor((ww - cw*screenScaleX) / 2)
+	screenOffsetY = math.Floor((wh - ch*screenScaleY) / 2)
+}
+
+// drawCanvasToScreen desenha o canvas na tela conforme a política de escala
+// O buffer físico do canvas é reduzido à escala lógica e depois ampliado para os
+// pixels do dispositivo, de modo que com PixelDensity(DisplayDensity()) não há reamostragem.
+func drawCanvasToScreen(screen *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(1/canvas.density, 1/canvas.density)
+	op.GeoM.Scale(screenScaleX, screenScaleY)
+	op.GeoM.Translate(screenOffsetX, screenOffsetY)
+	op.GeoM.Scale(screenDeviceScale, screenDeviceScale)
+	if windowSmoothing && windowScaling != INTEGER_SCALE {
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(canvas.img, op)
+}
+
+// screenToCanvas converte uma posição na tela (pixels do dispositivo) para coordenadas do canvas
+func screenToCanvas(x, y float64) (float64, float64) {
+	x, y = x/screenDeviceScale, y/screenDeviceScale
+	return (x - screenOffsetX) / screenScaleX, (y - screenOffsetY) / screenScaleY
+}